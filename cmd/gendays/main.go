@@ -0,0 +1,133 @@
+// Command gendays writes the package that blank-imports every day-package in internal/,
+// so all puzzles register themselves without maintaining the list by hand.
+//
+// It is run through `go generate ./internal/days`.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var dayDirPattern = regexp.MustCompile(`^day-\d{2}-[a-z0-9-]+$`)
+
+func main() {
+	var (
+		internalDir = flag.String("dir", "..", "directory holding the day-packages")
+		outputFile  = flag.String("out", "days_gen.go", "file to write the generated imports to")
+		packageName = flag.String("pkg", "days", "package name of the generated file")
+	)
+	flag.Parse()
+
+	if err := run(*internalDir, *outputFile, *packageName); err != nil {
+		fmt.Fprintf(os.Stderr, "gendays: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(internalDir, outputFile, packageName string) error {
+	absInternalDir, err := filepath.Abs(internalDir)
+	if err != nil {
+		return err
+	}
+	moduleRoot, err := findModuleRoot(absInternalDir)
+	if err != nil {
+		return err
+	}
+	modulePath, err := readModulePath(moduleRoot)
+	if err != nil {
+		return err
+	}
+	relInternalDir, err := filepath.Rel(moduleRoot, absInternalDir)
+	if err != nil {
+		return err
+	}
+
+	dayDirs, err := DayDirs(internalDir)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by cmd/gendays; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", packageName)
+	fmt.Fprintf(&buf, "import (\n")
+	for _, dayDir := range dayDirs {
+		fmt.Fprintf(&buf, "\t_ %q\n", modulePath+"/"+filepath.ToSlash(filepath.Join(relInternalDir, dayDir)))
+	}
+	fmt.Fprintf(&buf, ")\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(outputFile, source, 0o644)
+}
+
+// DayDirs returns the sorted names of all directories in dir that hold a day-package
+func DayDirs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dayDirs []string = make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || !dayDirPattern.MatchString(entry.Name()) {
+			continue
+		}
+
+		goFiles, err := filepath.Glob(filepath.Join(dir, entry.Name(), "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		if len(goFiles) > 0 {
+			dayDirs = append(dayDirs, entry.Name())
+		}
+	}
+	sort.Strings(dayDirs)
+
+	return dayDirs, nil
+}
+
+func readModulePath(root string) (string, error) {
+	file, err := os.Open(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "module ")), nil
+		}
+	}
+
+	return "", errors.New("no module directive found in go.mod")
+}
+
+func findModuleRoot(dir string) (string, error) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("no go.mod found")
+		}
+		dir = parent
+	}
+}
@@ -1,53 +1,246 @@
 package main
 
 import (
-	"bufio"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"runtime"
 	"strings"
 
-	_ "github.com/ewoutquax/advent-of-code-2018/internal/day-06-chronal-coordinates"
-	_ "github.com/ewoutquax/advent-of-code-2018/internal/day-07-the-sum-of-its-parts"
-	_ "github.com/ewoutquax/advent-of-code-2018/internal/day-13-mine-cart-madness"
-	_ "github.com/ewoutquax/advent-of-code-2018/internal/day-16-chronical-classification"
-	_ "github.com/ewoutquax/advent-of-code-2018/internal/day-19-go-with-the-flow"
+	_ "github.com/ewoutquax/advent-of-code-2018/internal/days"
 	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
 )
 
+const usage string = `Usage:
+  solver [run] [flags] [days]   Solve the selected days (default: the latest day)
+  solver verify [days]          Compare the answers with the answers.txt of each day (default: all days)
+  solver list                   List all registered days
+
+Days are selected by a list or range, like "05", "05,07", "05-13" or "all".
+The input of a day is the file given by -input, else 'day-XX.txt' in the inputs directory,
+else the input.txt embedded in the day-package.
+
+Flags of run:
+`
+
 func main() {
-	for _, puzzle := range getPuzzles() {
-		register.ExecDay(puzzle)
-	}
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
 
-func getPuzzles() (puzzles []string) {
-	var allPuzzles []string = register.GetAllDays()
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var command string = "run"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") && isCommand(args[0]) {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "list":
+		fmt.Fprintln(stdout, strings.Join(register.GetAllDays(), "\n"))
+		return 0
+	case "verify":
+		return verifyDays(args, stdout, stderr)
+	case "help":
+		printUsage(stderr, newRunFlags(stderr))
+		return 0
+	default:
+		return runDays(args, stdin, stdout, stderr)
+	}
+}
 
-	selection := readUserInput(fmt.Sprintf("Which puzzle to run %s:\n", allPuzzles))
-	switch selection {
-	case "":
-		latestPuzzle := allPuzzles[len(allPuzzles)-1]
-		fmt.Printf("Running latest puzzle: %s\n\n", latestPuzzle)
-		puzzles = []string{latestPuzzle}
-	case "all":
-		fmt.Printf("Running all puzzles\n\n")
-		puzzles = allPuzzles
+func isCommand(arg string) bool {
+	switch arg {
+	case "run", "verify", "list", "help":
+		return true
 	default:
-		fmt.Printf("Running selected puzzle: '%s'\n\n", selection)
-		puzzles = []string{selection}
+		return false
+	}
+}
+
+type runFlags struct {
+	*flag.FlagSet
+	days      *string
+	part      *string
+	inputFile *string
+	inputDir  *string
+	format    *string
+	stats     *bool
+	trace     *bool
+	workers   *int
+}
+
+func newRunFlags(stderr io.Writer) runFlags {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	rf := runFlags{
+		FlagSet:   fs,
+		days:      fs.String("days", "", `days to solve: "05", "05,07", "05-13" or "all"`),
+		part:      fs.String("part", "both", "part to solve: a, b or both"),
+		inputFile: fs.String("input", "", `override the input-file of the (single) selected day; "-" reads stdin`),
+		inputDir:  fs.String("inputs", "", "directory with an input-file 'day-XX.txt' per day (default $"+register.InputDirEnv+")"),
+		format:    fs.String("format", "text", "output format: text, json or csv"),
+		stats:     fs.Bool("stats", false, "print the time and memory used per day (to stderr, unless the format is text)"),
+		trace:     fs.Bool("trace", false, "write the trace of the days that have one, like the program of day 16, to stderr"),
+		workers:   fs.Int("workers", runtime.NumCPU(), "number of days to solve concurrently; -stats solves one day at a time"),
 	}
+	fs.Usage = func() { printUsage(stderr, rf) }
+
+	return rf
+}
 
-	return
+func printUsage(w io.Writer, rf runFlags) {
+	fmt.Fprint(w, usage)
+	rf.SetOutput(w)
+	rf.PrintDefaults()
 }
 
-func readUserInput(question string) string {
-	if len(os.Args) == 2 {
-		return os.Args[1]
+func runDays(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	rf := newRunFlags(stderr)
+	positional, err := parseInterleaved(rf.FlagSet, args)
+	if err != nil {
+		return 2
+	}
+
+	register.SetInputDir(*rf.inputDir)
+
+	// Allow the days as positional argument, like the solver used to do
+	var daySpec string = *rf.days
+	if daySpec == "" && len(positional) > 0 {
+		daySpec = strings.Join(positional, ",")
+	}
+
+	days, err := register.SelectDays(daySpec)
+	if err != nil {
+		fmt.Fprintf(stderr, "solver: %v\n", err)
+		return 2
+	}
+
+	parts, err := register.ParseParts(*rf.part)
+	if err != nil {
+		fmt.Fprintf(stderr, "solver: %v\n", err)
+		return 2
+	}
+
+	// The memory stats are measured process-wide, so they would mix the days that are solved concurrently
+	if *rf.stats {
+		if isFlagSet(rf.FlagSet, "workers") && *rf.workers != 1 {
+			fmt.Fprintf(stderr, "solver: -stats can't be combined with -workers %d\n", *rf.workers)
+			return 2
+		}
+		*rf.workers = 1
+	}
+
+	writer, err := newResultWriter(*rf.format, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "solver: %v\n", err)
+		return 2
 	}
 
-	fmt.Printf("%s", question)
-	reader := bufio.NewReader(os.Stdin)
-	text, _ := reader.ReadString('\n')
+	var options []register.Option = make([]register.Option, 0, len(parts)+1)
+	for _, part := range parts {
+		options = append(options, part)
+	}
+	if *rf.trace {
+		options = append(options, register.WithTrace(stderr))
+	}
+
+	var results []register.Result = make([]register.Result, 0, len(days))
+	if *rf.inputFile != "" {
+		if len(days) != 1 {
+			fmt.Fprintf(stderr, "solver: -input requires exactly one selected day, got %d\n", len(days))
+			return 2
+		}
+
+		input, err := readInput(*rf.inputFile, stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "solver: %v\n", err)
+			return 1
+		}
+		results = append(results, register.ExecDayWithInput(days[0], input, options...))
+	} else {
+		results = register.ExecDays(days, *rf.workers, options...)
+	}
+
+	if err := writer.Write(results); err != nil {
+		fmt.Fprintf(stderr, "solver: %v\n", err)
+		return 1
+	}
+
+	if *rf.stats {
+		// Keep the output of json and csv parseable
+		var statsOutput io.Writer = stderr
+		if *rf.format == "text" {
+			statsOutput = stdout
+			fmt.Fprintln(stdout)
+		}
+
+		if err := writeStats(statsOutput, results); err != nil {
+			fmt.Fprintf(stderr, "solver: %v\n", err)
+			return 1
+		}
+	}
+
+	if hasErrors(results, len(days) > 1) {
+		return 1
+	}
+	return 0
+}
+
+// Parse the flags, also when they follow the positional arguments
+func parseInterleaved(fs *flag.FlagSet, args []string) (positional []string, err error) {
+	for {
+		if err = fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func isFlagSet(fs *flag.FlagSet, name string) (found bool) {
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+
+	return found
+}
+
+func readInput(inputFile string, stdin io.Reader) (string, error) {
+	var raw []byte
+	var err error
+
+	if inputFile == "-" {
+		raw, err = io.ReadAll(stdin)
+	} else {
+		raw, err = os.ReadFile(inputFile)
+	}
+
+	return string(raw), err
+}
+
+// When several days are selected, a day without input is skipped instead of counted as an error
+func hasErrors(results []register.Result, skipMissingInput bool) bool {
+	for _, result := range results {
+		if skipMissingInput && errors.Is(result.Err, register.ErrMissingInput) {
+			continue
+		}
+		if result.Err != nil {
+			return true
+		}
+		for _, part := range result.Parts {
+			if part.Err != nil {
+				return true
+			}
+		}
+	}
 
-	return strings.Trim(text, "\n")
+	return false
 }
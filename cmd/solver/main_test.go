@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+const examplePolymer string = "dabAcCaCBAcCcaDA\n"
+
+// Run the solver with the given arguments and stdin, and return the exit status, stdout and stderr
+func runSolver(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Setenv(register.InputDirEnv, "")
+
+	var stdout, stderr strings.Builder
+	status := run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return status, stdout.String(), stderr.String()
+}
+
+func TestList(t *testing.T) {
+	status, stdout, _ := runSolver(t, "", "list")
+
+	assert.Equal(t, 0, status)
+	assert.Equal(t, strings.Join(register.GetAllDays(), "\n")+"\n", stdout)
+}
+
+func TestRunWithFormat(t *testing.T) {
+	testCases := map[string]string{
+		"text": "Result of day-05 / part-1: 10\nResult of day-05 / part-2: 4\n",
+		"csv":  "day,part,answer,error\n05,1,10,\n05,2,4,\n",
+	}
+
+	for format, expected := range testCases {
+		t.Run(format, func(t *testing.T) {
+			status, stdout, stderr := runSolver(t, examplePolymer, "run", "-input", "-", "-format", format, "05")
+
+			assert.Equal(t, 0, status, stderr)
+			assert.Equal(t, expected, stdout)
+		})
+	}
+}
+
+func TestRunWithJSONFormat(t *testing.T) {
+	status, stdout, stderr := runSolver(t, examplePolymer, "-input", "-", "-format", "json", "-days", "05")
+
+	assert.Equal(t, 0, status, stderr)
+
+	var records []answerRecord
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &records))
+	assert.Equal(t, []answerRecord{
+		{Day: "05", Part: 1, Answer: "10"},
+		{Day: "05", Part: 2, Answer: "4"},
+	}, records)
+}
+
+func TestRunSinglePart(t *testing.T) {
+	status, stdout, _ := runSolver(t, examplePolymer, "-input", "-", "-part", "b", "05")
+
+	assert.Equal(t, 0, status)
+	assert.Equal(t, "Result of day-05 / part-2: 4\n", stdout)
+}
+
+func TestRunWithInvalidArguments(t *testing.T) {
+	testCases := map[string][]string{
+		"unknown day":           {"99"},
+		"invalid part":          {"-part", "c", "05"},
+		"invalid format":        {"-format", "xml", "05"},
+		"input of several days": {"-input", "-", "05,06"},
+		"unknown flag":          {"-foo"},
+		"stats of several days": {"-stats", "-workers", "4", "05,06"},
+	}
+
+	for name, args := range testCases {
+		t.Run(name, func(t *testing.T) {
+			status, stdout, stderr := runSolver(t, "", args...)
+
+			assert.Equal(t, 2, status)
+			assert.Empty(t, stdout)
+			assert.NotEmpty(t, stderr)
+		})
+	}
+}
+
+func TestRunWithStats(t *testing.T) {
+	status, stdout, stderr := runSolver(t, examplePolymer, "-input", "-", "-format", "csv", "-stats", "05")
+
+	assert.Equal(t, 0, status)
+	assert.Equal(t, "day,part,answer,error\n05,1,10,\n05,2,4,\n", stdout)
+	assert.Regexp(t, `(?m)^\s*day\s+phase\s+time\s+allocs\s+memory\s*$`, stderr)
+	assert.Regexp(t, `(?m)^\s*05\s+part-2\s`, stderr)
+}
+
+func TestRunWithTrace(t *testing.T) {
+	status, stdout, stderr := runSolver(t, "", "-trace", "-part", "b", "05,16")
+
+	assert.Equal(t, 0, status)
+	assert.Regexp(t, `^Result of day-05 / part-2: \d+\nResult of day-16 / part-2: \d+\n$`, stdout)
+	assert.Regexp(t, `^  0: \w{4} `, stderr)
+
+	_, _, stderr = runSolver(t, "", "-part", "b", "16")
+	assert.Empty(t, stderr, "no trace without -trace")
+}
+
+func TestRunWithMissingInput(t *testing.T) {
+	status, stdout, _ := runSolver(t, "", "19")
+
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stdout, "Error in day-19: ")
+}
+
+func TestVerify(t *testing.T) {
+	status, stdout, _ := runSolver(t, "", "verify", "05", "19")
+
+	assert.Equal(t, 0, status)
+	assert.Equal(t, []string{
+		"day-05 / part-1: 9296                           OK",
+		"day-05 / part-2: 5534                           OK",
+		"day-19: missing input                            UNVERIFIED",
+	}, strings.Split(strings.TrimSpace(stdout), "\n"))
+}
+
+func TestVerifyWithRegression(t *testing.T) {
+	inputDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(inputDir, "day-05.txt"), []byte(examplePolymer), 0o644))
+
+	status, stdout, _ := runSolver(t, "", "verify", "-inputs", inputDir, "05")
+
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stdout, "day-05 / part-1: 10 (expected 9296)             REGRESSION\n")
+	assert.Contains(t, stdout, "day-05 / part-2: 4 (expected 5534)              REGRESSION\n")
+	assert.Contains(t, stdout, "\n2 failure(s)\n")
+}
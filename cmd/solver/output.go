@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+)
+
+type resultWriter interface {
+	Write(results []register.Result) error
+}
+
+type (
+	textWriter struct{ w io.Writer }
+	jsonWriter struct{ w io.Writer }
+	csvWriter  struct{ w io.Writer }
+)
+
+// A single answer, as written by the json- and csv-writers
+type answerRecord struct {
+	Day    string `json:"day"`
+	Part   int    `json:"part,omitempty"`
+	Answer string `json:"answer,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func newResultWriter(format string, w io.Writer) (resultWriter, error) {
+	switch format {
+	case "text":
+		return textWriter{w}, nil
+	case "json":
+		return jsonWriter{w}, nil
+	case "csv":
+		return csvWriter{w}, nil
+	default:
+		return nil, fmt.Errorf("invalid format '%s', expected text, json or csv", format)
+	}
+}
+
+func (tw textWriter) Write(results []register.Result) error {
+	for _, record := range toRecords(results) {
+		var err error
+
+		switch {
+		case record.Part == 0:
+			_, err = fmt.Fprintf(tw.w, "Error in day-%s: %s\n", record.Day, record.Error)
+		case record.Error != "":
+			_, err = fmt.Fprintf(tw.w, "Error in day-%s / part-%d: %s\n", record.Day, record.Part, record.Error)
+		default:
+			_, err = fmt.Fprintf(tw.w, "Result of day-%s / part-%d: %s\n", record.Day, record.Part, record.Answer)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (jw jsonWriter) Write(results []register.Result) error {
+	encoder := json.NewEncoder(jw.w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(toRecords(results))
+}
+
+func (cw csvWriter) Write(results []register.Result) error {
+	writer := csv.NewWriter(cw.w)
+
+	writer.Write([]string{"day", "part", "answer", "error"})
+	for _, record := range toRecords(results) {
+		var part string
+		if record.Part > 0 {
+			part = fmt.Sprintf("%d", record.Part)
+		}
+		writer.Write([]string{record.Day, part, record.Answer, record.Error})
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// Flatten the results to one record per answer; a day that failed to parse gives a single record without part
+func toRecords(results []register.Result) []answerRecord {
+	var records []answerRecord = make([]answerRecord, 0, len(results)*2)
+
+	for _, result := range results {
+		if result.Err != nil {
+			records = append(records, answerRecord{Day: result.Day, Error: result.Err.Error()})
+			continue
+		}
+
+		for _, part := range result.Parts {
+			record := answerRecord{Day: result.Day, Part: int(part.Part)}
+			if part.Err != nil {
+				record.Error = part.Err.Error()
+			} else {
+				record.Answer = part.Answer.String()
+			}
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+)
+
+// Write a table with the time and memory used per day, and per phase of each day
+func writeStats(w io.Writer, results []register.Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "day\tphase\ttime\tallocs\tmemory\t\n")
+
+	var total register.Stats
+	for _, result := range results {
+		writeStatsRow(tw, result.Day, "parse", result.ParseStats)
+		for _, part := range result.Parts {
+			writeStatsRow(tw, result.Day, fmt.Sprintf("part-%d", part.Part), part.Stats)
+		}
+		writeStatsRow(tw, result.Day, "total", result.TotalStats())
+
+		total = total.Add(result.TotalStats())
+	}
+	writeStatsRow(tw, "all", "total", total)
+
+	return tw.Flush()
+}
+
+func writeStatsRow(w io.Writer, day, phase string, stats register.Stats) {
+	fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t\n",
+		day,
+		phase,
+		stats.Duration.Round(time.Microsecond),
+		stats.Allocs,
+		formatBytes(stats.Bytes),
+	)
+}
+
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	var value float64 = float64(bytes) / unit
+	for _, suffix := range []string{"KiB", "MiB", "GiB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+
+	return fmt.Sprintf("%.1f TiB", value)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+)
+
+// Run the selected days, and compare their answers with the answers-file of each day
+func verifyDays(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	days := fs.String("days", "all", `days to verify: "05", "05,07", "05-13" or "all"`)
+	workers := fs.Int("workers", runtime.NumCPU(), "number of days to verify concurrently")
+	inputDir := fs.String("inputs", "", "directory with an input-file 'day-XX.txt' per day (default $"+register.InputDirEnv+")")
+
+	positional, err := parseInterleaved(fs, args)
+	if err != nil {
+		return 2
+	}
+
+	register.SetInputDir(*inputDir)
+
+	var daySpec string = *days
+	if len(positional) > 0 {
+		daySpec = strings.Join(positional, ",")
+	}
+
+	selectedDays, err := register.SelectDays(daySpec)
+	if err != nil {
+		fmt.Fprintf(stderr, "solver: %v\n", err)
+		return 2
+	}
+
+	var nrFailures int = 0
+	for _, result := range register.ExecDays(selectedDays, *workers) {
+		verification := register.VerifyResult(result)
+		day := verification.Day
+
+		// A day without input can't be checked, but that is no reason to fail the other days
+		if errors.Is(verification.Err, register.ErrMissingInput) {
+			fmt.Fprintf(stdout, "day-%s: %-40s %s\n", day, "missing input", register.VerdictUnverified)
+			continue
+		}
+
+		if verification.Err != nil {
+			fmt.Fprintf(stdout, "day-%s: %-40s FAILED\n", day, verification.Err)
+			nrFailures++
+			continue
+		}
+
+		for _, part := range verification.Parts {
+			if part.Verdict.IsFailure() {
+				nrFailures++
+			}
+			fmt.Fprintf(stdout, "day-%s / part-%d: %-30s %s\n", day, part.Part, describe(part), part.Verdict)
+		}
+	}
+
+	if nrFailures > 0 {
+		fmt.Fprintf(stdout, "\n%d failure(s)\n", nrFailures)
+		return 1
+	}
+	return 0
+}
+
+func describe(part register.PartVerification) string {
+	switch part.Verdict {
+	case register.VerdictFailed:
+		return part.Err.Error()
+	case register.VerdictRegression:
+		return fmt.Sprintf("%s (expected %s)", part.Answer, part.Expected)
+	default:
+		return part.Answer.String()
+	}
+}
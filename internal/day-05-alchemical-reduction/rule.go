@@ -0,0 +1,117 @@
+package day05alchemicalreduction
+
+import (
+	"fmt"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Decides which units react, and which units are of the same type
+type Rule interface {
+	Reacts(a, b rune) bool
+	UnitType(unit rune) rune // Units of the same type share their unit type, and are extracted together
+}
+
+var (
+	ASCIICase   Rule = asciiCase{}   // The same letter a–z in opposite cases reacts
+	UnicodeCase Rule = unicodeCase{} // Any letter and its other case react, following Unicode case folding
+)
+
+type asciiCase struct{}
+
+func (asciiCase) Reacts(a, b rune) bool {
+	return a^b == 'a'-'A' && a|('a'-'A') >= 'a' && a|('a'-'A') <= 'z'
+}
+
+func (asciiCase) UnitType(unit rune) rune {
+	if unit >= 'A' && unit <= 'Z' {
+		return unit + 'a' - 'A'
+	}
+	return unit
+}
+
+type unicodeCase struct{}
+
+func (u unicodeCase) Reacts(a, b rune) bool {
+	return a != b && u.UnitType(a) == u.UnitType(b)
+}
+
+// The smallest rune in the case folding orbit of the unit, so 'K', 'k' and the Kelvin sign share a type
+func (unicodeCase) UnitType(unit rune) rune {
+	var smallest rune = unit
+
+	for folded := unicode.SimpleFold(unit); folded != unit; folded = unicode.SimpleFold(folded) {
+		if folded < smallest {
+			smallest = folded
+		}
+	}
+
+	return smallest
+}
+
+type pairsRule struct {
+	partners  map[rune]rune
+	unitTypes map[rune]rune
+}
+
+// A rule where only the given pairs react, in either order. Each pair is a string of 2 units, like "+-";
+// the first unit of a pair is the type of both units
+func PairsRule(pairs ...string) (Rule, error) {
+	var rule pairsRule = pairsRule{
+		partners:  make(map[rune]rune, 2*len(pairs)),
+		unitTypes: make(map[rune]rune, 2*len(pairs)),
+	}
+
+	for _, pair := range pairs {
+		units := []rune(pair)
+		if len(units) != 2 || units[0] == units[1] {
+			return nil, fmt.Errorf("invalid pair '%s': expected 2 different units", pair)
+		}
+		for _, unit := range units {
+			if _, exists := rule.partners[unit]; exists {
+				return nil, fmt.Errorf("invalid pair '%s': unit '%c' is already part of another pair", pair, unit)
+			}
+		}
+
+		rule.partners[units[0]], rule.partners[units[1]] = units[1], units[0]
+		rule.unitTypes[units[0]], rule.unitTypes[units[1]] = units[0], units[0]
+	}
+
+	return rule, nil
+}
+
+func (p pairsRule) Reacts(a, b rune) bool {
+	partner, exists := p.partners[a]
+	return exists && partner == b
+}
+
+func (p pairsRule) UnitType(unit rune) rune {
+	if unitType, exists := p.unitTypes[unit]; exists {
+		return unitType
+	}
+	return unit
+}
+
+// The distinct unit types in the polymer, in order
+func UnitTypes(polymer string, rule Rule) []rune {
+	var seenASCII [utf8.RuneSelf]bool
+	var seen map[rune]bool = make(map[rune]bool)
+	var unitTypes []rune
+
+	for _, unit := range polymer {
+		unitType := rule.UnitType(unit)
+		if unitType < utf8.RuneSelf {
+			if !seenASCII[unitType] {
+				seenASCII[unitType] = true
+				unitTypes = append(unitTypes, unitType)
+			}
+		} else if !seen[unitType] {
+			seen[unitType] = true
+			unitTypes = append(unitTypes, unitType)
+		}
+	}
+	slices.Sort(unitTypes)
+
+	return unitTypes
+}
@@ -1,11 +1,15 @@
 package day05alchemicalreduction
 
 import (
+	"bufio"
+	"embed"
 	"fmt"
+	"io"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
-	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 )
 
 const Day string = "05"
@@ -19,12 +23,117 @@ func PolymerLengthAfterTrigger(polymer string) int {
 	return len(TriggerPolymer(polymer))
 }
 
+// Try removing every unit type from the polymer, and return the shortest result
 func ShortestPolymerWithExtraction(polymer string) string {
-	var shortestPolymer string = polymer
+	return NewReactor(ASCIICase).ShortestWithExtraction(polymer)
+}
+
+func TriggerPolymer(polymer string) string {
+	return NewReactor(ASCIICase).Trigger(polymer)
+}
+
+// Trigger the polymer while reading it, without holding more than the reduced polymer in memory.
+// Line endings are skipped
+func TriggerPolymerFromReader(r io.Reader) (string, error) {
+	return NewReactor(ASCIICase).TriggerFromReader(r)
+}
 
-	for _, extractable := range strings.Split(ALPHABET, "") {
-		result := triggerPolymerWithExtraction(polymer, extractable)
-		if len(shortestPolymer) > len(result) {
+// Two units that reacted, with their positions in the polymer, counting units from 0
+type Reaction struct {
+	Left, Right         int
+	LeftUnit, RightUnit rune
+}
+
+func (r Reaction) String() string {
+	return fmt.Sprintf("%d-%d: %c%c", r.Left, r.Right, r.LeftUnit, r.RightUnit)
+}
+
+// Triggers polymers by the rule, reporting every reaction to Trace when set
+type Reactor struct {
+	Rule  Rule
+	Trace func(Reaction)
+}
+
+func NewReactor(rule Rule) *Reactor {
+	return &Reactor{Rule: rule}
+}
+
+func (r *Reactor) Trigger(polymer string) string {
+	if r.onBytes() {
+		return string(reduceBytes(make(byteStack, 0, len(polymer)), polymer))
+	}
+
+	return r.reduce(r.newStack(len(polymer)), polymer).String()
+}
+
+// Trigger the polymer like Trigger, while reading it. Line endings are skipped
+func (r *Reactor) TriggerFromReader(reader io.Reader) (string, error) {
+	var buffered *bufio.Reader = bufio.NewReader(reader)
+
+	if r.onBytes() {
+		var s byteStack
+		for {
+			current, err := buffered.ReadByte()
+			if err == io.EOF {
+				return string(s), nil
+			}
+			if err != nil {
+				return "", err
+			}
+
+			if current != '\n' && current != '\r' {
+				s = s.add(current)
+			}
+		}
+	}
+
+	var s stack = r.newStack(0)
+	for position := 0; ; position++ {
+		current, _, err := buffered.ReadRune()
+		if err == io.EOF {
+			return s.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if current != '\n' && current != '\r' {
+			r.add(&s, current, position)
+		}
+	}
+}
+
+// Try removing every unit type of the polymer, and return the shortest result. The extractions run
+// concurrently, without being traced.
+// When every unit reacts with at most one other unit, removing a type never lets the remaining units react
+// differently, so the extractions start from the reduced polymer. Other rules, like UnicodeCase, where 's',
+// 'S' and 'ſ' all react with each other, start from the polymer itself
+func (r *Reactor) ShortestWithExtraction(polymer string) string {
+	var reduced string = r.Trigger(polymer)
+	var start string = polymer
+	if isPairing(r.Rule) {
+		start = reduced
+	}
+
+	var untraced *Reactor = &Reactor{Rule: r.Rule}
+	// Extracting a type that only occurs in the polymer before reducing it, gives the reduced polymer itself
+	var unitTypes []rune = UnitTypes(start, r.Rule)
+	var results []string = make([]string, len(unitTypes))
+	var wg sync.WaitGroup
+
+	for idx, unitType := range unitTypes {
+		wg.Add(1)
+		go func(idx int, unitType rune) {
+			defer wg.Done()
+			results[idx] = untraced.triggerWithExtraction(start, unitType)
+		}(idx, unitType)
+	}
+	wg.Wait()
+
+	// Compare the number of units, not bytes, as units may take more than a byte
+	var shortestPolymer string = reduced
+	for _, result := range results {
+		if utf8.RuneCountInString(shortestPolymer) > utf8.RuneCountInString(result) {
 			shortestPolymer = result
 		}
 	}
@@ -32,31 +141,122 @@ func ShortestPolymerWithExtraction(polymer string) string {
 	return shortestPolymer
 }
 
-func triggerPolymerWithExtraction(polymer, extractable string) string {
-	polymer = strings.Replace(polymer, extractable, "", -1)
-	polymer = strings.Replace(polymer, strings.ToUpper(extractable), "", -1)
+func (r *Reactor) triggerWithExtraction(polymer string, extractable rune) string {
+	if r.onBytes() && extractable < utf8.RuneSelf {
+		// Only letters are extracted in both cases, by ignoring the case bit
+		var ignored byte = 0
+		if extractable >= 'a' && extractable <= 'z' {
+			ignored = caseBit
+		}
 
-	return TriggerPolymer(polymer)
+		var s byteStack = make(byteStack, 0, len(polymer))
+		for idx := 0; idx < len(polymer); idx++ {
+			if polymer[idx]|ignored != byte(extractable) {
+				s = s.add(polymer[idx])
+			}
+		}
+		return string(s)
+	}
+
+	var s stack = r.newStack(len(polymer))
+	var position int = 0
+	for _, current := range polymer {
+		if r.Rule.UnitType(current) != extractable {
+			r.add(&s, current, position)
+		}
+		position++
+	}
+
+	return s.String()
 }
 
-func TriggerPolymer(polymer string) string {
-	var idx int = 0
-	var doAnotherRun bool = true
-
-	for doAnotherRun {
-		idx = 0
-		doAnotherRun = false
-		for idx < len(polymer)-1 {
-			if DoesReact(polymer[idx : idx+2]) {
-				polymer = polymer[:idx] + polymer[idx+2:]
-				doAnotherRun = true
-			} else {
-				idx++
-			}
+// Without a trace, ASCIICase reduces the bytes of the polymer, avoiding a call through the rule per unit
+func (r *Reactor) onBytes() bool {
+	return r.Rule == ASCIICase && r.Trace == nil
+}
+
+// Whether every unit reacts with at most one other unit
+func isPairing(rule Rule) bool {
+	switch rule.(type) {
+	case asciiCase, pairsRule:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *Reactor) newStack(capacity int) stack {
+	var s stack = stack{units: make([]rune, 0, capacity)}
+	if r.Trace != nil {
+		s.positions = make([]int, 0, capacity)
+	}
+
+	return s
+}
+
+func (r *Reactor) reduce(s stack, polymer string) stack {
+	var position int = 0
+	for _, current := range polymer {
+		r.add(&s, current, position)
+		position++
+	}
+
+	return s
+}
+
+// Each new unit either reacts with the last survivor, or survives itself
+func (r *Reactor) add(s *stack, current rune, position int) {
+	if last := len(s.units) - 1; last >= 0 && r.Rule.Reacts(s.units[last], current) {
+		if r.Trace != nil {
+			r.Trace(Reaction{
+				Left: s.positions[last], Right: position,
+				LeftUnit: s.units[last], RightUnit: current,
+			})
+			s.positions = s.positions[:last]
 		}
+		s.units = s.units[:last]
+	} else {
+		s.units = append(s.units, current)
+		if r.Trace != nil {
+			s.positions = append(s.positions, position)
+		}
+	}
+}
+
+// The units that survived so far; their positions in the polymer are only kept when tracing
+type stack struct {
+	units     []rune
+	positions []int
+}
+
+func (s stack) String() string {
+	return string(s.units)
+}
+
+// The surviving units, when reducing the bytes of the polymer by ASCIICase
+type byteStack []byte
+
+func reduceBytes(s byteStack, polymer string) byteStack {
+	for idx := 0; idx < len(polymer); idx++ {
+		s = s.add(polymer[idx])
+	}
+
+	return s
+}
+
+// Returns the stack after adding the unit, like append
+func (s byteStack) add(unit byte) byteStack {
+	if last := len(s) - 1; last >= 0 && reactsBytes(s[last], unit) {
+		return s[:last]
 	}
+	return append(s, unit)
+}
+
+// The bit that differs between the lower and upper case of an ASCII letter
+const caseBit byte = 'a' - 'A'
 
-	return polymer
+func reactsBytes(a, b byte) bool {
+	return a^b == caseBit && a|caseBit >= 'a' && a|caseBit <= 'z'
 }
 
 func DoesReact(input string) bool {
@@ -65,19 +265,26 @@ func DoesReact(input string) bool {
 	return parts[0] != parts[1] && strings.ToUpper(parts[0]) == strings.ToUpper(parts[1])
 }
 
-func solvePart1(inputFile string) {
-	line := utils.ReadFileAsLine(inputFile)
-	polymer := TriggerPolymer(line)
-	fmt.Printf("Result of day-%s / part-1: %d\n", Day, len(polymer))
+type solver struct {
+	polymer string
 }
 
-func solvePart2(inputFile string) {
-	line := utils.ReadFileAsLine(inputFile)
-	polymer := ShortestPolymerWithExtraction(line)
-	fmt.Printf("Result of day-%s / part-2: %d\n", Day, len(polymer))
+func (s *solver) Parse(input string) error {
+	s.polymer = input
+	return nil
 }
 
+func (s *solver) SolvePart1() (register.Answer, error) {
+	return register.IntAnswer(PolymerLengthAfterTrigger(s.polymer)), nil
+}
+
+func (s *solver) SolvePart2() (register.Answer, error) {
+	return register.IntAnswer(ShortestPolymerLengthWithExtraction(s.polymer)), nil
+}
+
+//go:embed *.txt
+var files embed.FS
+
 func init() {
-	register.Day(Day+"a", solvePart1)
-	register.Day(Day+"b", solvePart2)
+	register.Day(Day, files, func() register.Solver { return &solver{} })
 }
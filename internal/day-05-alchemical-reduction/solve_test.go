@@ -1,9 +1,14 @@
 package day05alchemicalreduction_test
 
 import (
+	"fmt"
+	"math/rand"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	. "github.com/ewoutquax/advent-of-code-2018/internal/day-05-alchemical-reduction"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -41,3 +46,184 @@ func TestShortestPolymerLengthWithExtraction(t *testing.T) {
 	length := ShortestPolymerLengthWithExtraction("dabAcCaCBAcCcaDA")
 	assert.Equal(t, 4, length)
 }
+
+func TestTriggerPolymerFromReader(t *testing.T) {
+	result, err := TriggerPolymerFromReader(strings.NewReader("dabAcCaCBAcCcaDA\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "dabCBAcaDA", result)
+}
+
+func TestTriggerPolymerLikeRescanning(t *testing.T) {
+	// Rescanning all of the input takes seconds
+	polymer := utils.ReadFileAsLine("input.txt")[:5000]
+
+	assert.Equal(t, triggerPolymerRescanning(polymer), TriggerPolymer(polymer))
+}
+
+func TestTriggerPolymerOnlyReactsLetters(t *testing.T) {
+	// '@' and '`' differ from each other in the same bit as the case of letters
+	assert.Equal(t, "@`b", TriggerPolymer("@`aAb"))
+}
+
+func TestReactionTrace(t *testing.T) {
+	var reactions []string
+	reactor := NewReactor(ASCIICase)
+	reactor.Trace = func(reaction Reaction) {
+		reactions = append(reactions, reaction.String())
+	}
+
+	result := reactor.Trigger("dabAcCaCBAcCcaDA")
+
+	assert.Equal(t, "dabCBAcaDA", result)
+	assert.Equal(t, []string{"4-5: cC", "3-6: Aa", "10-11: cC"}, reactions)
+}
+
+func TestRules(t *testing.T) {
+	plusMinus, _ := PairsRule("+-", "xy")
+
+	testCases := []struct {
+		rule     Rule
+		a, b     rune
+		expected bool
+	}{
+		{ASCIICase, 'a', 'A', true},
+		{ASCIICase, 'é', 'É', false},
+		{UnicodeCase, 'é', 'É', true},
+		{UnicodeCase, 'k', '\u212a', true}, // The Kelvin sign
+		{UnicodeCase, 'k', 'k', false},
+		{UnicodeCase, '1', '1', false},
+		{plusMinus, '+', '-', true},
+		{plusMinus, 'y', 'x', true},
+		{plusMinus, 'x', '-', false},
+		{plusMinus, 'a', 'A', false},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, testCase.rule.Reacts(testCase.a, testCase.b), fmt.Sprintf("%c%c", testCase.a, testCase.b))
+	}
+}
+
+func TestPairsRuleInvalid(t *testing.T) {
+	_, err := PairsRule("+-", "x")
+	assert.EqualError(t, err, "invalid pair 'x': expected 2 different units")
+
+	_, err = PairsRule("+-", "-x")
+	assert.EqualError(t, err, "invalid pair '-x': unit '-' is already part of another pair")
+}
+
+func TestUnitTypes(t *testing.T) {
+	plusMinus, _ := PairsRule("+-", "xy")
+
+	assert.Equal(t, []rune("abcd"), UnitTypes("dabAcCaCBAcCcaDA", ASCIICase))
+	assert.Equal(t, []rune("+ax"), UnitTypes("a-+yx-", plusMinus))
+}
+
+func TestShortestWithExtractionCustomRules(t *testing.T) {
+	plusMinus, _ := PairsRule("+-", "xy")
+	assert.Equal(t, "", NewReactor(plusMinus).ShortestWithExtraction("+x-y+-"))
+	assert.Equal(t, "+x-y", NewReactor(plusMinus).Trigger("+x-y+-"))
+
+	assert.Equal(t, "dð", NewReactor(UnicodeCase).ShortestWithExtraction("dÉéaðbB"))
+}
+
+func TestShortestWithExtractionUnicodeGroups(t *testing.T) {
+	// 's', 'S' and 'ſ' all react with each other, so reducing before extracting loses the best result
+	assert.Equal(t, "", NewReactor(UnicodeCase).ShortestWithExtraction("SSKsſ"))
+
+	random := rand.New(rand.NewSource(5))
+	units := []rune("sSſkK\u212aab")
+	for i := 0; i < 2000; i++ {
+		var polymer []rune = make([]rune, 1+random.Intn(12))
+		for idx := range polymer {
+			polymer[idx] = units[random.Intn(len(units))]
+		}
+
+		assert.Equal(t, shortestByExtractingFirst(string(polymer)), utf8.RuneCountInString(NewReactor(UnicodeCase).ShortestWithExtraction(string(polymer))), string(polymer))
+	}
+}
+
+func TestTriggerWithTraceLikeWithout(t *testing.T) {
+	polymer := utils.ReadFileAsLine("input.txt")
+	traced := NewReactor(ASCIICase)
+	traced.Trace = func(Reaction) {}
+
+	assert.Equal(t, TriggerPolymer(polymer), traced.Trigger(polymer))
+	assert.Equal(t, ShortestPolymerWithExtraction(polymer), traced.ShortestWithExtraction(polymer))
+}
+
+func BenchmarkPart1(b *testing.B) {
+	polymer := utils.ReadFileAsLine("input.txt")
+
+	for i := 0; i < b.N; i++ {
+		PolymerLengthAfterTrigger(polymer)
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	polymer := utils.ReadFileAsLine("input.txt")
+
+	for i := 0; i < b.N; i++ {
+		ShortestPolymerLengthWithExtraction(polymer)
+	}
+}
+
+func BenchmarkPart1Rescanning(b *testing.B) {
+	polymer := utils.ReadFileAsLine("input.txt")
+
+	for i := 0; i < b.N; i++ {
+		triggerPolymerRescanning(polymer)
+	}
+}
+
+func BenchmarkPart2Rescanning(b *testing.B) {
+	polymer := utils.ReadFileAsLine("input.txt")
+
+	for i := 0; i < b.N; i++ {
+		var shortest int = len(polymer)
+		for _, extractable := range strings.Split(ALPHABET, "") {
+			extracted := strings.Replace(polymer, extractable, "", -1)
+			extracted = strings.Replace(extracted, strings.ToUpper(extractable), "", -1)
+			if length := len(triggerPolymerRescanning(extracted)); shortest > length {
+				shortest = length
+			}
+		}
+	}
+}
+
+// Extract every unit type from the polymer itself, before reducing it by UnicodeCase
+func shortestByExtractingFirst(polymer string) int {
+	var shortest int = utf8.RuneCountInString(NewReactor(UnicodeCase).Trigger(polymer))
+	for _, unitType := range UnitTypes(polymer, UnicodeCase) {
+		var extracted []rune
+		for _, unit := range polymer {
+			if UnicodeCase.UnitType(unit) != unitType {
+				extracted = append(extracted, unit)
+			}
+		}
+		shortest = min(shortest, utf8.RuneCountInString(NewReactor(UnicodeCase).Trigger(string(extracted))))
+	}
+
+	return shortest
+}
+
+// The former implementation, which rescans the polymer after every reaction
+func triggerPolymerRescanning(polymer string) string {
+	var idx int = 0
+	var doAnotherRun bool = true
+
+	for doAnotherRun {
+		idx = 0
+		doAnotherRun = false
+		for idx < len(polymer)-1 {
+			if DoesReact(polymer[idx : idx+2]) {
+				polymer = polymer[:idx] + polymer[idx+2:]
+				doAnotherRun = true
+			} else {
+				idx++
+			}
+		}
+	}
+
+	return polymer
+}
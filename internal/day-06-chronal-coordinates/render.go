@@ -0,0 +1,139 @@
+package day06chronalcoordinates
+
+import (
+	"bufio"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/grid"
+)
+
+type RenderOptions struct {
+	SafeRegion   *grid.Dense[bool] // Overlaid on the areas, when set
+	MarkInfinite bool              // Set the locations of infinite areas apart
+}
+
+// Symbols of the areas, repeating for universes with more areas
+const AREA_SYMBOLS string = "abcdefghijklmnopqrstuvwxyz"
+
+// Write the ownership as text, like the example of the puzzle: every area is a letter, in capitals at its
+// own coordinates, and ties are dots. The safe region is drawn as '#', and locations of infinite areas as '~'
+func RenderASCII(w io.Writer, ownership Ownership, options RenderOptions) error {
+	var buffered *bufio.Writer = bufio.NewWriter(w)
+	var sites map[Location]int = ownership.sites()
+	var box grid.Box = ownership.Owners.Bounds()
+
+	for y := box.Min.Y; y <= box.Max.Y; y++ {
+		for x := box.Min.X; x <= box.Max.X; x++ {
+			buffered.WriteByte(ownership.symbol(grid.Pt(x, y), sites, options))
+		}
+		buffered.WriteByte('\n')
+	}
+
+	return buffered.Flush()
+}
+
+func (o Ownership) symbol(l Location, sites map[Location]int, options RenderOptions) byte {
+	if idx, isSite := sites[l]; isSite {
+		return AREA_SYMBOLS[idx%len(AREA_SYMBOLS)] - 'a' + 'A'
+	}
+	if options.SafeRegion != nil && options.SafeRegion.Get(l) {
+		return '#'
+	}
+
+	owner := o.Owners.Get(l)
+	switch {
+	case owner == TIE:
+		return '.'
+	case options.MarkInfinite && o.Areas[owner].IsInfinite:
+		return '~'
+	default:
+		return AREA_SYMBOLS[owner%len(AREA_SYMBOLS)]
+	}
+}
+
+// Write the ownership as a PNG image, with a square of scale by scale pixels per location. Every area gets
+// its own colour, with its coordinates in black. Ties are dark grey, infinite areas are pale, and the safe
+// region is lightened
+func RenderPNG(w io.Writer, ownership Ownership, options RenderOptions, scale int) error {
+	var box grid.Box = ownership.Owners.Bounds()
+	var img *image.RGBA = image.NewRGBA(image.Rect(0, 0, box.Width()*scale, box.Height()*scale))
+	var sites map[Location]int = ownership.sites()
+
+	box.Each(func(l Location) {
+		c := ownership.colour(l, sites, options)
+		for dy := 0; dy < scale; dy++ {
+			for dx := 0; dx < scale; dx++ {
+				img.Set((l.X-box.Min.X)*scale+dx, (l.Y-box.Min.Y)*scale+dy, c)
+			}
+		}
+	})
+
+	return png.Encode(w, img)
+}
+
+func (o Ownership) colour(l Location, sites map[Location]int, options RenderOptions) color.RGBA {
+	if _, isSite := sites[l]; isSite {
+		return color.RGBA{A: 255}
+	}
+
+	var c color.RGBA = color.RGBA{R: 64, G: 64, B: 64, A: 255}
+	if owner := o.Owners.Get(l); owner != TIE {
+		c = AreaColour(owner, options.MarkInfinite && o.Areas[owner].IsInfinite)
+	}
+
+	if options.SafeRegion != nil && options.SafeRegion.Get(l) {
+		c = color.RGBA{R: c.R/2 + 128, G: c.G/2 + 128, B: c.B/2 + 128, A: 255}
+	}
+
+	return c
+}
+
+// A distinct colour per area: the hues are spread by the golden ratio, so neighbouring indexes differ most
+func AreaColour(idx int, pale bool) color.RGBA {
+	var hue float64 = math.Mod(float64(idx)*0.618033988749895, 1)
+	var saturation float64 = 0.75
+	if pale {
+		saturation = 0.2
+	}
+
+	return hsvToRGB(hue, saturation, 0.9)
+}
+
+func hsvToRGB(h, s, v float64) color.RGBA {
+	var sector float64 = math.Floor(h * 6)
+	var f float64 = h*6 - sector
+	var p, q, t float64 = v * (1 - s), v * (1 - f*s), v * (1 - (1-f)*s)
+
+	var r, g, b float64
+	switch int(sector) % 6 {
+	case 0:
+		r, g, b = v, t, p
+	case 1:
+		r, g, b = q, v, p
+	case 2:
+		r, g, b = p, v, t
+	case 3:
+		r, g, b = p, q, v
+	case 4:
+		r, g, b = t, p, v
+	default:
+		r, g, b = v, p, q
+	}
+
+	return color.RGBA{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 255}
+}
+
+// The index of the area at each of their coordinates
+func (o Ownership) sites() map[Location]int {
+	var sites map[Location]int = make(map[Location]int, len(o.Areas))
+
+	for idx, area := range o.Areas {
+		sites[area.Location] = idx
+	}
+
+	return sites
+}
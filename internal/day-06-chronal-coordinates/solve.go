@@ -1,25 +1,16 @@
 package day06chronalcoordinates
 
 import (
+	"embed"
 	"fmt"
 	"math"
-	"regexp"
 
+	"github.com/ewoutquax/advent-of-code-2018/pkg/grid"
 	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
 	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 )
 
-type Location struct {
-	X int
-	Y int
-}
-
-func (l Location) ManhattanDistance(other Location) int {
-	return utils.Abs(l.X-other.X) + utils.Abs(l.Y-other.Y)
-}
-func (l Location) toS() string {
-	return fmt.Sprintf("%d,%d", l.X, l.Y)
-}
+type Location = grid.Point
 
 type Area struct {
 	Location
@@ -35,130 +26,164 @@ type Universe struct {
 	MaxY  int
 }
 
+// The smallest box containing all areas
+func (u Universe) Bounds() grid.Box {
+	return grid.Box{
+		Min: grid.Pt(u.MinX, u.MinY),
+		Max: grid.Pt(u.MaxX, u.MaxY),
+	}
+}
+
 const Day string = "06"
 const INFINITE int = math.MaxInt
+const TIE int = -1
+
+//go:embed *.txt
+var files embed.FS
 
 func init() {
-	register.Day(Day+"a", solvePart1)
-	register.Day(Day+"b", solvePart2)
+	register.Day(Day, files, func() register.Solver { return &solver{} })
+}
+
+type solver struct {
+	universe Universe
+}
+
+func (s *solver) Parse(input string) (err error) {
+	s.universe, err = ParseInput(utils.SplitLines(input))
+	return
 }
 
-func solvePart1(inputFile string) {
-	lines := utils.ReadFileAsLines(inputFile)
-	universe := ParseInput(lines)
-	var count int = MaxFiniteSize(universe)
+func (s *solver) SolvePart1() (register.Answer, error) {
+	count, err := MaxFiniteSize(s.universe, Manhattan)
+	if err != nil {
+		return nil, err
+	}
 
-	fmt.Printf("Result of day-%s / part-1: %d\n", Day, count)
+	return register.IntAnswer(count), nil
 }
 
-func solvePart2(inputFile string) {
-	lines := utils.ReadFileAsLines(inputFile)
-	universe := ParseInput(lines)
-	var size int = SizeOfRegionWithDistanceToAllBelowThreshold(universe, 10_000)
+func (s *solver) SolvePart2() (register.Answer, error) {
+	var size int = SizeOfRegionWithDistanceToAllBelowThreshold(s.universe, 10_000, Manhattan)
 
-	fmt.Printf("Result of day-%s / part-2: %d\n", Day, size)
+	return register.IntAnswer(size), nil
 }
 
-func SizeOfRegionWithDistanceToAllBelowThreshold(universe Universe, threshold int) int {
-	var size int = 0
+// The number of locations whose total distance to all areas is below the threshold
+func SizeOfRegionWithDistanceToAllBelowThreshold(universe Universe, threshold int, metric Metric) int {
+	return universe.Space(metric).RegionSize(threshold)
+}
 
-	for y := universe.MinY - threshold; y <= universe.MinY+threshold; y++ {
-		for x := universe.MinX - threshold; x <= universe.MinX+threshold; x++ {
-			var currentLocation Location = Location{x, y}
-			var totalDistance int = 0
-			for _, area := range universe.Areas {
-				if totalDistance > threshold {
-					break
-				}
-				totalDistance += currentLocation.ManhattanDistance(area.Location)
-			}
-			if totalDistance < threshold {
-				size++
-			}
-		}
+// The box with all locations whose total distance to the areas can be below the threshold.
+// Every step away from the bounds adds 1 per area, so the region can't extend further than threshold/areas
+func RegionWindow(universe Universe, threshold int) grid.Box {
+	if len(universe.Areas) == 0 || threshold <= 0 {
+		return grid.Box{Min: grid.Pt(0, 0), Max: grid.Pt(-1, -1)}
 	}
 
-	return size
-}
-
-func MaxFiniteSize(universe Universe) int {
-	var maxSize int = 0
-
-	for y := universe.MinY - 1; y <= universe.MaxY+1; y++ {
-		for x := universe.MinX - 1; x <= universe.MaxX+1; x++ {
-			currentLocation := Location{X: x, Y: y}
-			var closestArea *Area
-			minDistance := INFINITE
-			var unique bool = true
-
-			for _, area := range universe.Areas {
-				distance := currentLocation.ManhattanDistance(area.Location)
-				if minDistance == distance {
-					unique = false
-				}
-				if minDistance > distance {
-					unique = true
-					minDistance = distance
-					closestArea = area
-				}
-			}
-
-			if unique {
-				closestArea.Size++
-				if currentLocation.X < universe.MinX ||
-					currentLocation.X > universe.MaxX ||
-					currentLocation.Y < universe.MinY ||
-					currentLocation.Y > universe.MaxY {
-					closestArea.IsInfinite = true
-				}
-				if maxSize < closestArea.Size && !closestArea.IsInfinite {
-					maxSize = closestArea.Size
-				}
-			}
-		}
+	return universe.Bounds().Expand(regionMargin(len(universe.Areas), threshold))
+}
+
+// The size of the largest area that is not infinite, after updating the sizes of the areas
+func MaxFiniteSize(universe Universe, metric Metric) (int, error) {
+	sizes, infinite, err := universe.updateAreas(universe.Space(metric))
+	if err != nil {
+		return 0, err
 	}
 
-	return maxSize
+	return maxFiniteSize(sizes, infinite), nil
 }
 
-func ParseInput(lines []string) Universe {
-	var areas []*Area = make([]*Area, 0, len(lines))
-	var minX, minY int = INFINITE, INFINITE
-	var maxX, maxY int = 0, 0
+// Set the size of every area, and whether it's infinite
+func (u Universe) updateAreas(space Space) ([]int, []bool, error) {
+	sizes, infinite, err := space.AreaSizes()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	for _, line := range lines {
-		ex := regexp.MustCompile(`(-?\d+)+`)
-		matches := ex.FindAllString(line, -1)
+	for idx, area := range u.Areas {
+		area.Size = sizes[idx]
+		area.IsInfinite = infinite[idx]
+	}
 
-		area := Area{
-			Location: Location{
-				X: utils.ConvStrToI(matches[0]),
-				Y: utils.ConvStrToI(matches[1]),
-			},
-			IsInfinite: false,
-			Size:       0,
-		}
-		areas = append(areas, &area)
+	return sizes, infinite, nil
+}
+
+// Which area is closest to every location: the index of the area in the universe, or TIE
+type Ownership struct {
+	Universe
+	Owners *grid.Dense[int] // Covers the bounds of the universe, expanded by the margin of the metric
+}
 
-		if minX > area.X {
-			minX = area.X
-		}
-		if minY > area.Y {
-			minY = area.Y
-		}
-		if maxX < area.X {
-			maxX = area.X
-		}
-		if maxY < area.Y {
-			maxY = area.Y
-		}
+// The area owning the location; false for ties, and for locations outside the ownership
+func (o Ownership) Owner(l Location) (*Area, bool) {
+	if !o.Owners.Bounds().Contains(l) {
+		return nil, false
 	}
 
+	if owner := o.Owners.Get(l); owner != TIE {
+		return o.Areas[owner], true
+	}
+
+	return nil, false
+}
+
+// Find the owner of every location around the areas, and update the size of the areas, and whether
+// they're infinite. For Manhattan distances, the locations cover the bounds expanded by 1
+func BuildOwnership(universe Universe, metric Metric) (Ownership, error) {
+	var space Space = universe.Space(metric)
+	if _, _, err := universe.updateAreas(space); err != nil {
+		return Ownership{}, err
+	}
+
+	var ownership Ownership = Ownership{
+		Universe: universe,
+		Owners:   grid.NewDense[int](universe.Bounds().Expand(space.margin())),
+	}
+	ownership.Owners.Bounds().Each(func(currentLocation Location) {
+		ownership.Owners.Set(currentLocation, space.owner(Coordinate{X: currentLocation.X, Y: currentLocation.Y}))
+	})
+
+	return ownership, nil
+}
+
+// The locations within the box whose total distance to all areas is below the threshold
+func SafeRegion(universe Universe, threshold int, box grid.Box, metric Metric) *grid.Dense[bool] {
+	var space Space = universe.Space(metric)
+	var region *grid.Dense[bool] = grid.NewDense[bool](box)
+
+	box.Each(func(currentLocation Location) {
+		region.Set(currentLocation, space.isSafe(Coordinate{X: currentLocation.X, Y: currentLocation.Y}, threshold))
+	})
+
+	return region
+}
+
+// Parse coordinates like "1, 6"; errors point to the offending line and column
+func ParseInput(lines []string) (Universe, error) {
+	space, err := ParseSpace(lines, Manhattan)
+	if err != nil {
+		return Universe{}, err
+	}
+	if len(space.Sites) > 0 && space.Dims != 2 {
+		return Universe{}, &utils.ParseError{Line: 1, Input: lines[0], Err: fmt.Errorf("expected 2 coordinates, got %d", space.Dims)}
+	}
+
+	var areas []*Area = make([]*Area, 0, len(space.Sites))
+	var locations []Location = make([]Location, 0, len(space.Sites))
+	for _, site := range space.Sites {
+		area := Area{Location: grid.Pt(site.X, site.Y)}
+		areas = append(areas, &area)
+		locations = append(locations, area.Location)
+	}
+
+	var bounds grid.Box = grid.BoundingBox(locations...)
+
 	return Universe{
 		Areas: areas,
-		MinX:  minX,
-		MinY:  minY,
-		MaxX:  maxX,
-		MaxY:  maxY,
-	}
+		MinX:  bounds.Min.X,
+		MinY:  bounds.Min.Y,
+		MaxX:  bounds.Max.X,
+		MaxY:  bounds.Max.Y,
+	}, nil
 }
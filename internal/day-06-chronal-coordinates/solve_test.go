@@ -1,16 +1,25 @@
 package day06chronalcoordinates_test
 
 import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
 	"testing"
 
 	. "github.com/ewoutquax/advent-of-code-2018/internal/day-06-chronal-coordinates"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/grid"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestParseInput(t *testing.T) {
-	universe := ParseInput(testInput())
+	universe, err := ParseInput(testInput())
 
 	assert := assert.New(t)
+	assert.NoError(err)
 	assert.IsType(universe, Universe{})
 	assert.Len(universe.Areas, 6)
 
@@ -27,20 +36,420 @@ func TestParseInput(t *testing.T) {
 	assert.Equal(9, universe.MaxY)
 }
 
+func TestParseInputWithInvalidLine(t *testing.T) {
+	_, err := ParseInput([]string{"1, 6", "foo"})
+	assert.EqualError(t, err, "line 2: parse 'foo': expected 2 or 3 coordinates, got 1")
+
+	_, err = ParseInput([]string{"1, 6", "3, x"})
+	assert.EqualError(t, err, "line 2, column 3: parse ' x': invalid syntax")
+
+	_, err = ParseInput([]string{"1, 6, 3"})
+	assert.EqualError(t, err, "line 1: parse '1, 6, 3': expected 2 coordinates, got 3")
+}
+
 func TestMaxFiniteSize(t *testing.T) {
-	universe := ParseInput(testInput())
-	maxSize := MaxFiniteSize(universe)
+	universe, _ := ParseInput(testInput())
+	maxSize, err := MaxFiniteSize(universe, Manhattan)
+
+	assert.NoError(t, err)
 
 	assert.Equal(t, 17, maxSize)
 }
 
 func TestSizeOfRegionWithDistanceToAllBelowThreshold(t *testing.T) {
-	universe := ParseInput(testInput())
+	universe, _ := ParseInput(testInput())
 
-	var size int = SizeOfRegionWithDistanceToAllBelowThreshold(universe, 32)
+	var size int = SizeOfRegionWithDistanceToAllBelowThreshold(universe, 32, Manhattan)
 	assert.Equal(t, 16, size)
 }
 
+func TestSizeOfRegionLikeBruteForce(t *testing.T) {
+	universe, _ := ParseInput(testInput())
+
+	for _, threshold := range []int{0, 1, 6, 20, 32, 33, 50, 100, 500} {
+		assert.Equal(t, sizeOfRegionBruteForce(universe, threshold), SizeOfRegionWithDistanceToAllBelowThreshold(universe, threshold, Manhattan), threshold)
+	}
+}
+
+func TestSizeOfRegionLikeSafeRegion(t *testing.T) {
+	universe, _ := ParseInput(utils.ReadFileAsLines("input.txt"))
+
+	var expected int = 0
+	window := RegionWindow(universe, 10_000)
+	region := SafeRegion(universe, 10_000, window.Expand(1), Manhattan)
+	window.Expand(1).Each(func(l Location) {
+		if region.Get(l) {
+			expected++
+			assert.True(t, window.Contains(l))
+		}
+	})
+
+	assert.Equal(t, expected, SizeOfRegionWithDistanceToAllBelowThreshold(universe, 10_000, Manhattan))
+}
+
+func TestRegionWindow(t *testing.T) {
+	universe, _ := ParseInput(testInput())
+
+	assert.Equal(t, grid.Box{Min: Location{X: -4, Y: -4}, Max: Location{X: 13, Y: 14}}, RegionWindow(universe, 32))
+	assert.Equal(t, universe.Bounds(), RegionWindow(universe, 6))
+}
+
+func TestBuildOwnership(t *testing.T) {
+	universe, _ := ParseInput(testInput())
+	ownership, _ := BuildOwnership(universe, Manhattan)
+
+	assert.Equal(t, grid.Box{Min: Location{X: 0, Y: 0}, Max: Location{X: 9, Y: 10}}, ownership.Owners.Bounds())
+
+	area, ok := ownership.Owner(Location{X: 4, Y: 3})
+	assert.True(t, ok)
+	assert.Equal(t, Location{X: 3, Y: 4}, area.Location)
+
+	_, ok = ownership.Owner(Location{X: 5, Y: 0})
+	assert.False(t, ok, "tie")
+	assert.Equal(t, TIE, ownership.Owners.Get(Location{X: 5, Y: 0}))
+
+	assert.True(t, universe.Areas[0].IsInfinite)
+	assert.False(t, universe.Areas[4].IsInfinite)
+	assert.Equal(t, 17, universe.Areas[4].Size)
+
+	// Again, like when rendering after MaxFiniteSize
+	maxSize, _ := MaxFiniteSize(universe, Manhattan)
+	assert.Equal(t, 17, maxSize)
+	BuildOwnership(universe, Manhattan)
+	assert.Equal(t, 17, universe.Areas[4].Size)
+	assert.True(t, universe.Areas[0].IsInfinite)
+}
+
+func TestRenderASCII(t *testing.T) {
+	universe, _ := ParseInput(testInput())
+	ownership, _ := BuildOwnership(universe, Manhattan)
+
+	var out strings.Builder
+	assert.NoError(t, RenderASCII(&out, ownership, RenderOptions{}))
+	assert.Equal(t, strings.Join([]string{
+		"aaaaa.cccc",
+		"aAaaa.cccc",
+		"aaaddecccc",
+		"aadddeccCc",
+		"..dDdeeccc",
+		"bb.deEeecc",
+		"bBb.eeee..",
+		"bbb.eeefff",
+		"bbb.eeffff",
+		"bbb.ffffFf",
+		"bbb.ffffff",
+		"",
+	}, "\n"), out.String())
+}
+
+func TestRenderASCIIWithSafeRegion(t *testing.T) {
+	universe, _ := ParseInput(testInput())
+	ownership, _ := BuildOwnership(universe, Manhattan)
+	options := RenderOptions{
+		SafeRegion:   SafeRegion(universe, 32, ownership.Owners.Bounds(), Manhattan),
+		MarkInfinite: true,
+	}
+
+	var out strings.Builder
+	assert.NoError(t, RenderASCII(&out, ownership, options))
+	assert.Equal(t, strings.Join([]string{
+		"~~~~~.~~~~",
+		"~A~~~.~~~~",
+		"~~~dde~~~~",
+		"~~d###~~C~",
+		"..#D###~~~",
+		"~~###E#e~~",
+		"~B~###ee..",
+		"~~~.eee~~~",
+		"~~~.ee~~~~",
+		"~~~.~~~~F~",
+		"~~~.~~~~~~",
+		"",
+	}, "\n"), out.String())
+}
+
+func TestRenderPNG(t *testing.T) {
+	universe, _ := ParseInput(testInput())
+	ownership, _ := BuildOwnership(universe, Manhattan)
+
+	var out bytes.Buffer
+	assert.NoError(t, RenderPNG(&out, ownership, RenderOptions{}, 3))
+
+	img, err := png.Decode(&out)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 30, 33), img.Bounds())
+
+	// The coordinates of area A, a tie, and a location owned by area D
+	assert.Equal(t, color.RGBA{A: 255}, color.RGBAModel.Convert(img.At(4, 4)))
+	assert.Equal(t, color.RGBA{R: 64, G: 64, B: 64, A: 255}, color.RGBAModel.Convert(img.At(16, 1)))
+	assert.Equal(t, AreaColour(3, false), color.RGBAModel.Convert(img.At(14, 11)))
+}
+
+func TestParseSpace(t *testing.T) {
+	space, err := ParseSpace(testInput(), Chebyshev)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, space.Dims)
+	assert.Equal(t, Chebyshev, space.Metric)
+	assert.Equal(t, Coordinate{X: 8, Y: 9}, space.Sites[5])
+
+	space, err = ParseSpace([]string{"1, 2, 3", "-4, 5, 6"}, Euclidean)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, space.Dims)
+	assert.Equal(t, []Coordinate{{X: 1, Y: 2, Z: 3}, {X: -4, Y: 5, Z: 6}}, space.Sites)
+
+	low, high := space.Bounds()
+	assert.Equal(t, Coordinate{X: -4, Y: 2, Z: 3}, low)
+	assert.Equal(t, Coordinate{X: 1, Y: 5, Z: 6}, high)
+
+	_, err = ParseSpace([]string{"1, 2, 3", "4, 5"}, Manhattan)
+	assert.ErrorContains(t, err, "line 2")
+	assert.ErrorContains(t, err, "expected 3 coordinates")
+
+	_, err = ParseSpace([]string{"1, x"}, Manhattan)
+	assert.ErrorContains(t, err, "line 1")
+}
+
+func TestUniverseMetrics(t *testing.T) {
+	testCases := map[Metric]struct {
+		maxSize    int
+		regionSize int
+		margin     int
+	}{
+		Manhattan: {17, 16, 1},
+		Chebyshev: {10, 80, 8},
+		Euclidean: {16, 62, 8},
+	}
+
+	for metric, expected := range testCases {
+		universe, _ := ParseInput(testInput())
+
+		maxSize, err := MaxFiniteSize(universe, metric)
+		assert.NoError(t, err)
+		assert.Equal(t, expected.maxSize, maxSize, metric)
+		assert.Equal(t, expected.regionSize, SizeOfRegionWithDistanceToAllBelowThreshold(universe, 32, metric), metric)
+
+		ownership, err := BuildOwnership(universe, metric)
+		assert.NoError(t, err)
+		assert.Equal(t, universe.Bounds().Expand(expected.margin), ownership.Owners.Bounds(), metric)
+		assert.Equal(t, expected.maxSize, universe.Areas[4].Size, metric)
+
+		var nrSafe int = 0
+		region := SafeRegion(universe, 32, RegionWindow(universe, 32), metric)
+		region.Bounds().Each(func(l Location) {
+			if region.Get(l) {
+				nrSafe++
+			}
+		})
+		assert.Equal(t, expected.regionSize, nrSafe, metric)
+	}
+}
+
+func TestSpaceMetrics(t *testing.T) {
+	testCases := map[Metric]struct {
+		maxSize    int
+		regionSize int
+	}{
+		Manhattan: {17, 16},
+		Chebyshev: {10, 80},
+		Euclidean: {16, 62},
+	}
+
+	for metric, expected := range testCases {
+		space, _ := ParseSpace(testInput(), metric)
+
+		maxSize, err := space.MaxFiniteSize()
+		assert.NoError(t, err)
+		assert.Equal(t, expected.maxSize, maxSize, metric)
+		assert.Equal(t, expected.regionSize, space.RegionSize(32), metric)
+		assert.Equal(t, []bool{true, true, true, false, false, true}, space.InfiniteAreas(), metric)
+	}
+}
+
+func TestInfiniteAreasOwnFarLocations(t *testing.T) {
+	const margin int = 1000
+
+	for _, metric := range []Metric{Manhattan, Chebyshev, Euclidean} {
+		space, _ := ParseSpace(testInput(), metric)
+		assertInfiniteAreasOwnFarLocations(t, space, margin)
+	}
+}
+
+func TestCoincidentSitesOwnNothing(t *testing.T) {
+	lines := append(testInput(), "1, 1", "5, 5")
+
+	for _, metric := range []Metric{Manhattan, Chebyshev, Euclidean} {
+		space, _ := ParseSpace(lines, metric)
+
+		sizes, infinite, err := space.AreaSizes()
+		assert.NoError(t, err)
+		for _, idx := range []int{0, 4, 6, 7} {
+			assert.Equal(t, 0, sizes[idx], "%v, site %d", metric, idx)
+			assert.False(t, infinite[idx], "%v, site %d", metric, idx)
+		}
+		assertInfiniteAreasOwnFarLocations(t, space, 1000)
+	}
+}
+
+func TestSpace3D(t *testing.T) {
+	var tetrahedron []string = []string{"0, 0, 0", "4, 0, 0", "0, 4, 0", "0, 0, 4", "1, 1, 1"}
+
+	space, _ := ParseSpace(tetrahedron, Euclidean)
+	sizes, infinite, err := space.AreaSizes()
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true, true, false}, infinite)
+	assert.Equal(t, 69, sizes[4])
+
+	// Above the face opposite the origin, the inner site is closer than any corner
+	space.Metric = Manhattan
+	assert.Equal(t, []bool{true, true, true, true, true}, space.InfiniteAreas())
+
+	for _, threshold := range []int{10, 12, 20, 40} {
+		var expected int = 0
+		low, high := space.Bounds()
+		for z := low.Z - threshold; z <= high.Z+threshold; z++ {
+			for y := low.Y - threshold; y <= high.Y+threshold; y++ {
+				for x := low.X - threshold; x <= high.X+threshold; x++ {
+					var total float64 = 0
+					for _, site := range space.Sites {
+						total += Manhattan.Distance(Coordinate{X: x, Y: y, Z: z}, site)
+					}
+					if total < float64(threshold) {
+						expected++
+					}
+				}
+			}
+		}
+		assert.Equal(t, expected, space.RegionSize(threshold), threshold)
+	}
+}
+
+func TestAreaSizesWithLargeEuclideanArea(t *testing.T) {
+	// The finite area of "6, 7" reaches beyond twice the margin of the bounds
+	space, _ := ParseSpace([]string{"5, 11", "11, 10", "2, 4", "6, 7", "0, 7"}, Euclidean)
+
+	maxSize, err := space.MaxFiniteSize()
+	assert.NoError(t, err)
+	assert.Equal(t, bruteForceMaxFiniteSize(space, 200), maxSize)
+}
+
+func TestMetricString(t *testing.T) {
+	assert.Equal(t, "euclidean", Euclidean.String())
+	assert.Equal(t, "metric(7)", Metric(7).String())
+	assert.Equal(t, Manhattan.Distance(Coordinate{X: 1}, Coordinate{Y: 2}), Metric(7).Distance(Coordinate{X: 1}, Coordinate{Y: 2}))
+}
+
+func BenchmarkParseInput(b *testing.B) {
+	lines := utils.ReadFileAsLines("input.txt")
+
+	for i := 0; i < b.N; i++ {
+		ParseInput(lines)
+	}
+}
+
+func BenchmarkPart1(b *testing.B) {
+	lines := utils.ReadFileAsLines("input.txt")
+
+	for i := 0; i < b.N; i++ {
+		universe, _ := ParseInput(lines)
+		MaxFiniteSize(universe, Manhattan)
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	universe, _ := ParseInput(utils.ReadFileAsLines("input.txt"))
+
+	for i := 0; i < b.N; i++ {
+		SizeOfRegionWithDistanceToAllBelowThreshold(universe, 10_000, Manhattan)
+	}
+}
+
+func assertInfiniteAreasOwnFarLocations(t *testing.T, space Space, margin int) {
+	low, high := space.Bounds()
+
+	var farOwners []bool = make([]bool, len(space.Sites))
+	for y := low.Y - margin; y <= high.Y+margin; y++ {
+		for x := low.X - margin; x <= high.X+margin; x++ {
+			if y != low.Y-margin && y != high.Y+margin && x != low.X-margin && x != high.X+margin {
+				continue
+			}
+			if owner := nearestSite(space, Coordinate{X: x, Y: y}); owner != TIE {
+				farOwners[owner] = true
+			}
+		}
+	}
+
+	assert.Equal(t, farOwners, space.InfiniteAreas(), space.Metric)
+}
+
+// Count the locations of every site within the margin around the bounds; areas reaching the edge are infinite
+func bruteForceMaxFiniteSize(space Space, margin int) int {
+	low, high := space.Bounds()
+
+	var sizes []int = make([]int, len(space.Sites))
+	var infinite []bool = make([]bool, len(space.Sites))
+	for y := low.Y - margin; y <= high.Y+margin; y++ {
+		for x := low.X - margin; x <= high.X+margin; x++ {
+			owner := nearestSite(space, Coordinate{X: x, Y: y})
+			if owner == TIE {
+				continue
+			}
+
+			sizes[owner]++
+			if y == low.Y-margin || y == high.Y+margin || x == low.X-margin || x == high.X+margin {
+				infinite[owner] = true
+			}
+		}
+	}
+
+	var maxSize int = 0
+	for idx, size := range sizes {
+		if !infinite[idx] {
+			maxSize = max(maxSize, size)
+		}
+	}
+
+	return maxSize
+}
+
+func nearestSite(space Space, location Coordinate) int {
+	var owner int = TIE
+	var minDistance float64 = math.Inf(1)
+	for idx, site := range space.Sites {
+		distance := space.Metric.Distance(location, site)
+		if distance == minDistance {
+			owner = TIE
+		}
+		if distance < minDistance {
+			minDistance = distance
+			owner = idx
+		}
+	}
+	return owner
+}
+
+// The former implementation, which sums the distances to all areas for every location
+func sizeOfRegionBruteForce(universe Universe, threshold int) int {
+	var size int = 0
+
+	for y := universe.MinY - threshold; y <= universe.MinY+threshold; y++ {
+		for x := universe.MinX - threshold; x <= universe.MinX+threshold; x++ {
+			var currentLocation Location = grid.Pt(x, y)
+			var totalDistance int = 0
+			for _, area := range universe.Areas {
+				if totalDistance > threshold {
+					break
+				}
+				totalDistance += currentLocation.ManhattanDistance(area.Location)
+			}
+			if totalDistance < threshold {
+				size++
+			}
+		}
+	}
+
+	return size
+}
+
 func testInput() []string {
 	return []string{
 		"1, 1",
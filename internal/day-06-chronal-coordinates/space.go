@@ -0,0 +1,486 @@
+package day06chronalcoordinates
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
+)
+
+// How far the counting window may grow, relative to the margin of the metric
+const MAX_MARGIN_FACTOR int = 16
+
+var ErrWindowTooSmall = errors.New("finite area reaches the edge of the counting window")
+
+// A location in 2 or 3 dimensions; in 2 dimensions, Z is always 0
+type Coordinate struct {
+	X, Y, Z int
+}
+
+func (c Coordinate) axis(idx int) int {
+	return [3]int{c.X, c.Y, c.Z}[idx]
+}
+
+func (c Coordinate) withAxis(idx, value int) Coordinate {
+	switch idx {
+	case 0:
+		c.X = value
+	case 1:
+		c.Y = value
+	default:
+		c.Z = value
+	}
+	return c
+}
+
+type Metric uint
+
+const (
+	Manhattan Metric = iota
+	Chebyshev
+	Euclidean
+)
+
+func (m Metric) String() string {
+	switch m {
+	case Manhattan:
+		return "manhattan"
+	case Chebyshev:
+		return "chebyshev"
+	case Euclidean:
+		return "euclidean"
+	default:
+		return fmt.Sprintf("metric(%d)", m)
+	}
+}
+
+func (m Metric) Distance(a, b Coordinate) float64 {
+	if m == Euclidean {
+		return math.Sqrt(float64(m.rank(a, b)))
+	}
+	return float64(m.rank(a, b))
+}
+
+// Orders distances like Distance, in integers: the squared distance for Euclidean, so ties are exact.
+// Unknown metrics measure like Manhattan
+func (m Metric) rank(a, b Coordinate) int {
+	switch m {
+	case Chebyshev:
+		return max(utils.Abs(a.X-b.X), utils.Abs(a.Y-b.Y), utils.Abs(a.Z-b.Z))
+	case Euclidean:
+		return (a.X-b.X)*(a.X-b.X) + (a.Y-b.Y)*(a.Y-b.Y) + (a.Z-b.Z)*(a.Z-b.Z)
+	default:
+		return manhattan(a, b)
+	}
+}
+
+func manhattan(a, b Coordinate) int {
+	return utils.Abs(a.X-b.X) + utils.Abs(a.Y-b.Y) + utils.Abs(a.Z-b.Z)
+}
+
+// The coordinates of the areas, and how distances between them are measured
+type Space struct {
+	Sites  []Coordinate
+	Dims   int
+	Metric Metric
+}
+
+// The areas of the universe, in 2 dimensions
+func (u Universe) Space(metric Metric) Space {
+	var sites []Coordinate = make([]Coordinate, 0, len(u.Areas))
+	for _, area := range u.Areas {
+		sites = append(sites, Coordinate{X: area.X, Y: area.Y})
+	}
+
+	return Space{Sites: sites, Dims: 2, Metric: metric}
+}
+
+// Parse coordinates like "1, 6" or "1, 6, 3"; all lines must have the same number of dimensions
+func ParseSpace(lines []string, metric Metric) (Space, error) {
+	var space Space = Space{Sites: make([]Coordinate, 0, len(lines)), Metric: metric}
+
+	for idx, line := range lines {
+		parts := strings.Split(line, ",")
+		if len(parts) < 2 || len(parts) > 3 {
+			return Space{}, &utils.ParseError{Line: idx + 1, Input: line, Err: fmt.Errorf("expected 2 or 3 coordinates, got %d", len(parts))}
+		}
+		if space.Dims == 0 {
+			space.Dims = len(parts)
+		}
+		if len(parts) != space.Dims {
+			return Space{}, &utils.ParseError{Line: idx + 1, Input: line, Err: fmt.Errorf("expected %d coordinates, like the first line, got %d", space.Dims, len(parts))}
+		}
+
+		var site Coordinate
+		var column int = 1
+		for axis, part := range parts {
+			value, err := utils.ParseIntAt(part, idx+1, column)
+			if err != nil {
+				return Space{}, err
+			}
+			site = site.withAxis(axis, value)
+			column += len(part) + 1
+		}
+		space.Sites = append(space.Sites, site)
+	}
+
+	return space, nil
+}
+
+// The smallest box containing all sites, as its lowest and highest corner
+func (s Space) Bounds() (Coordinate, Coordinate) {
+	if len(s.Sites) == 0 {
+		return Coordinate{}, Coordinate{}
+	}
+
+	var low, high Coordinate = s.Sites[0], s.Sites[0]
+	for _, site := range s.Sites[1:] {
+		low = Coordinate{X: min(low.X, site.X), Y: min(low.Y, site.Y), Z: min(low.Z, site.Z)}
+		high = Coordinate{X: max(high.X, site.X), Y: max(high.Y, site.Y), Z: max(high.Z, site.Z)}
+	}
+
+	return low, high
+}
+
+// The size of the largest area that is not infinite
+func (s Space) MaxFiniteSize() (int, error) {
+	sizes, infinite, err := s.AreaSizes()
+	if err != nil {
+		return 0, err
+	}
+
+	return maxFiniteSize(sizes, infinite), nil
+}
+
+func maxFiniteSize(sizes []int, infinite []bool) int {
+	var maxSize int = 0
+	for idx, size := range sizes {
+		if !infinite[idx] && maxSize < size {
+			maxSize = size
+		}
+	}
+
+	return maxSize
+}
+
+// The number of locations closest to each site, and whether that number is infinite.
+// Finite areas are counted within the bounds, expanded by the margin of the metric. For Euclidean distances
+// a finite area can reach further; the margin is then doubled until no finite area reaches the edge
+func (s Space) AreaSizes() ([]int, []bool, error) {
+	var infinite []bool = s.InfiniteAreas()
+	for margin := s.margin(); ; margin *= 2 {
+		sizes, err := s.countAreas(infinite, margin)
+		if err == nil || margin >= MAX_MARGIN_FACTOR*s.margin() {
+			return sizes, infinite, err
+		}
+	}
+}
+
+func (s Space) countAreas(infinite []bool, margin int) ([]int, error) {
+	var sizes []int = make([]int, len(s.Sites))
+	var reachesEdge bool = false
+
+	low, high := s.expandedBounds(margin)
+	s.each(low, high, func(location Coordinate) {
+		owner := s.owner(location)
+		if owner == TIE {
+			return
+		}
+
+		sizes[owner]++
+		if !infinite[owner] && s.onEdge(location, low, high) {
+			reachesEdge = true
+		}
+	})
+
+	if reachesEdge {
+		return nil, ErrWindowTooSmall
+	}
+
+	return sizes, nil
+}
+
+// Which areas extend infinitely:
+//
+//   - Manhattan: moving a location outside the bounds further away adds 1 to the distance to every site, so
+//     an area owning a location just outside the bounds owns all locations beyond it
+//   - Chebyshev: the same holds further away, once the distance along the axes outside the bounds dominates:
+//     beyond the largest extent of the bounds
+//   - Euclidean: the areas of the sites on the boundary of their convex hull are unbounded, except for sites
+//     sharing their coordinates with another site: those tie everywhere, and own nothing
+func (s Space) InfiniteAreas() []bool {
+	if s.Metric == Euclidean {
+		return s.onConvexHull()
+	}
+
+	var infinite []bool = make([]bool, len(s.Sites))
+	low, high := s.expandedBounds(s.margin())
+	s.eachOnEdge(low, high, func(location Coordinate) {
+		if owner := s.owner(location); owner != TIE {
+			infinite[owner] = true
+		}
+	})
+
+	return infinite
+}
+
+// The number of locations whose total distance to all sites is below the threshold
+func (s Space) RegionSize(threshold int) int {
+	if len(s.Sites) == 0 || threshold <= 0 {
+		return 0
+	}
+
+	low, high := s.expandedBounds(regionMargin(len(s.Sites), threshold))
+	if s.Metric == Manhattan {
+		return s.regionSizeManhattan(threshold, low, high)
+	}
+
+	var size int = 0
+	s.each(low, high, func(location Coordinate) {
+		if s.isSafe(location, threshold) {
+			size++
+		}
+	})
+
+	return size
+}
+
+// Every distance is at least the distance along a single axis, so no location with a total distance below
+// the threshold is further from the bounds than threshold/sites
+func regionMargin(nrSites, threshold int) int {
+	return (threshold - 1) / nrSites
+}
+
+// Whether the total distance from the location to all sites is below the threshold
+func (s Space) isSafe(location Coordinate, threshold int) bool {
+	var total float64 = 0
+	for _, site := range s.Sites {
+		if total += s.Metric.Distance(location, site); total >= float64(threshold) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Manhattan distances add up per axis, so the totals per axis are computed once, and then combined
+func (s Space) regionSizeManhattan(threshold int, low, high Coordinate) int {
+	var distances [3][]int
+	for axis := 0; axis < 3; axis++ {
+		var coordinates []int = make([]int, 0, len(s.Sites))
+		for _, site := range s.Sites {
+			coordinates = append(coordinates, site.axis(axis))
+		}
+		distances[axis] = axisDistances(coordinates, low.axis(axis), high.axis(axis))
+	}
+
+	// The last axis is sorted, to count the positions along it that stay below the threshold
+	var last []int = distances[s.Dims-1]
+	slices.Sort(last)
+
+	var size int = 0
+	for _, distanceX := range distances[0] {
+		if s.Dims == 2 {
+			size += sort.SearchInts(last, threshold-distanceX)
+			continue
+		}
+		for _, distanceY := range distances[1] {
+			size += sort.SearchInts(last, threshold-distanceX-distanceY)
+		}
+	}
+
+	return size
+}
+
+// The total distance from every position in from..to to all coordinates.
+// Moving one step right adds 1 for every coordinate to the left, and removes 1 for every other
+func axisDistances(coordinates []int, from, to int) []int {
+	var sorted []int = slices.Clone(coordinates)
+	slices.Sort(sorted)
+
+	var distances []int = make([]int, 0, to-from+1)
+	var distance int = 0
+	for _, coordinate := range sorted {
+		distance += utils.Abs(coordinate - from)
+	}
+
+	var nrLeft int = sort.SearchInts(sorted, from+1) // Coordinates at or left of the position
+	for position := from; position <= to; position++ {
+		distances = append(distances, distance)
+		distance += nrLeft - (len(sorted) - nrLeft)
+
+		for nrLeft < len(sorted) && sorted[nrLeft] <= position+1 {
+			nrLeft++
+		}
+	}
+
+	return distances
+}
+
+// The index of the closest site, or TIE
+func (s Space) owner(location Coordinate) int {
+	var owner int = TIE
+	var minRank int = INFINITE
+
+	for idx, site := range s.Sites {
+		var rank int
+		if s.Metric == Manhattan {
+			// Inlined; the common case, on the hot path of every area count
+			rank = manhattan(location, site)
+		} else {
+			rank = s.Metric.rank(location, site)
+		}
+		if rank == minRank {
+			owner = TIE
+		}
+		if rank < minRank {
+			minRank = rank
+			owner = idx
+		}
+	}
+
+	return owner
+}
+
+// How far outside the bounds the areas are counted
+func (s Space) margin() int {
+	if s.Metric == Manhattan {
+		return 1
+	}
+
+	low, high := s.Bounds()
+	var extent int = 1
+	for axis := 0; axis < s.Dims; axis++ {
+		extent = max(extent, high.axis(axis)-low.axis(axis))
+	}
+
+	return extent
+}
+
+func (s Space) expandedBounds(margin int) (Coordinate, Coordinate) {
+	low, high := s.Bounds()
+	for axis := 0; axis < s.Dims; axis++ {
+		low = low.withAxis(axis, low.axis(axis)-margin)
+		high = high.withAxis(axis, high.axis(axis)+margin)
+	}
+
+	return low, high
+}
+
+func (s Space) onEdge(location, low, high Coordinate) bool {
+	for axis := 0; axis < s.Dims; axis++ {
+		if location.axis(axis) == low.axis(axis) || location.axis(axis) == high.axis(axis) {
+			return true
+		}
+	}
+	return false
+}
+
+// Call fn for every location from low to high, including both
+func (s Space) each(low, high Coordinate, fn func(location Coordinate)) {
+	for z := low.Z; z <= high.Z; z++ {
+		for y := low.Y; y <= high.Y; y++ {
+			for x := low.X; x <= high.X; x++ {
+				fn(Coordinate{X: x, Y: y, Z: z})
+			}
+		}
+	}
+}
+
+// Call fn for every location on the edge of the box from low to high: whole rows along x on the edge of the
+// other axes, and only both ends of the rows inside
+func (s Space) eachOnEdge(low, high Coordinate, fn func(location Coordinate)) {
+	rowHigh := high.withAxis(0, low.X)
+	s.each(low, rowHigh, func(rowStart Coordinate) {
+		if s.onEdge(rowStart.withAxis(0, low.X+1), low.withAxis(0, low.X), rowHigh) {
+			s.each(rowStart, rowStart.withAxis(0, high.X), fn)
+			return
+		}
+
+		fn(rowStart)
+		if high.X != low.X {
+			fn(rowStart.withAxis(0, high.X))
+		}
+	})
+}
+
+// A site is on the boundary of the convex hull, when a line (2D) or plane (3D) through the site has all
+// other sites on one side. When all sites are on a single line (2D) or plane (3D), every site is
+func (s Space) onConvexHull() []bool {
+	var onHull []bool = make([]bool, len(s.Sites))
+
+	for idx, site := range s.Sites {
+		if s.isCoincident(idx) {
+			continue
+		}
+
+		var foundPlane bool = false
+		var isSupporting bool = false
+
+		s.eachNormal(site, func(normal Coordinate) bool {
+			foundPlane = true
+			var below, above bool
+			for _, other := range s.Sites {
+				side := dot(normal, sub(other, site))
+				below = below || side < 0
+				above = above || side > 0
+			}
+			isSupporting = !(below && above)
+			return !isSupporting
+		})
+
+		onHull[idx] = !foundPlane || isSupporting
+	}
+
+	return onHull
+}
+
+// Whether another site has the same coordinates
+func (s Space) isCoincident(idx int) bool {
+	for other, site := range s.Sites {
+		if other != idx && site == s.Sites[idx] {
+			return true
+		}
+	}
+	return false
+}
+
+// Call fn with the normal of every line or plane through the site and other sites, until fn returns false
+func (s Space) eachNormal(site Coordinate, fn func(normal Coordinate) bool) {
+	for _, a := range s.Sites {
+		if s.Dims == 2 {
+			direction := sub(a, site)
+			if direction != (Coordinate{}) && !fn(Coordinate{X: -direction.Y, Y: direction.X}) {
+				return
+			}
+			continue
+		}
+
+		for _, b := range s.Sites {
+			normal := cross(sub(a, site), sub(b, site))
+			if normal != (Coordinate{}) && !fn(normal) {
+				return
+			}
+		}
+	}
+}
+
+func sub(a, b Coordinate) Coordinate {
+	return Coordinate{X: a.X - b.X, Y: a.Y - b.Y, Z: a.Z - b.Z}
+}
+
+func dot(a, b Coordinate) int {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+func cross(a, b Coordinate) Coordinate {
+	return Coordinate{
+		X: a.Y*b.Z - a.Z*b.Y,
+		Y: a.Z*b.X - a.X*b.Z,
+		Z: a.X*b.Y - a.Y*b.X,
+	}
+}
@@ -0,0 +1,241 @@
+package day07thesumofitsparts
+
+import (
+	"fmt"
+	"sort"
+)
+
+// When a step can start at the earliest, and at the latest without delaying the whole build
+type StepTiming struct {
+	Step          string
+	Duration      int
+	EarliestStart int
+	LatestStart   int
+}
+
+func (s StepTiming) EarliestEnd() int {
+	return s.EarliestStart + s.Duration
+}
+
+func (s StepTiming) LatestEnd() int {
+	return s.LatestStart + s.Duration
+}
+
+// How long the step can be delayed without delaying the whole build
+func (s StepTiming) Slack() int {
+	return s.LatestStart - s.EarliestStart
+}
+
+func (s StepTiming) IsCritical() bool {
+	return s.Slack() == 0
+}
+
+type CriticalPath struct {
+	Timings map[string]StepTiming
+	Length  int // The minimum time to build all steps, with unlimited workers
+	Path    []string
+}
+
+// Compute the timing of every step from the items it is blocked by, with the remaining build time of
+// every item as its duration. The path is the chain of steps without slack, taking the alphabetically
+// first step when several are critical
+func AnalyseCriticalPath(universe Universe) (CriticalPath, error) {
+	return analyseCriticalPath(universe, universe.Durations())
+}
+
+// The minimum time to build all steps, when every step can start as soon as it is unblocked
+func MinimumTime(universe Universe) (int, error) {
+	criticalPath, err := AnalyseCriticalPath(universe)
+	if err != nil {
+		return 0, err
+	}
+
+	return criticalPath.Length, nil
+}
+
+func analyseCriticalPath(universe Universe, durations Durations) (CriticalPath, error) {
+	var duration map[string]int = make(map[string]int, len(universe.Items))
+	for name := range universe.Items {
+		stepDuration, err := durations(name)
+		if err != nil {
+			return CriticalPath{}, err
+		}
+		if stepDuration < 0 {
+			return CriticalPath{}, fmt.Errorf("negative build time for step '%s': %d", name, stepDuration)
+		}
+		duration[name] = stepDuration
+	}
+	var blocks map[string][]*Item = universe.blocks()
+
+	var earliestStart map[string]int = make(map[string]int, len(universe.Items))
+	var computeEarliestStart func(item *Item) int
+	computeEarliestStart = func(item *Item) int {
+		if start, exists := earliestStart[item.Name]; exists {
+			return start
+		}
+
+		var start int = 0
+		for _, blockingItem := range item.BlockedBy {
+			start = max(start, computeEarliestStart(blockingItem)+duration[blockingItem.Name])
+		}
+		earliestStart[item.Name] = start
+		return start
+	}
+
+	var length int = 0
+	for name, item := range universe.Items {
+		length = max(length, computeEarliestStart(item)+duration[name])
+	}
+
+	var latestStart map[string]int = make(map[string]int, len(universe.Items))
+	var computeLatestStart func(item *Item) int
+	computeLatestStart = func(item *Item) int {
+		if start, exists := latestStart[item.Name]; exists {
+			return start
+		}
+
+		var end int = length
+		for _, blockedItem := range blocks[item.Name] {
+			end = min(end, computeLatestStart(blockedItem))
+		}
+		latestStart[item.Name] = end - duration[item.Name]
+		return latestStart[item.Name]
+	}
+
+	var criticalPath CriticalPath = CriticalPath{
+		Timings: make(map[string]StepTiming, len(universe.Items)),
+		Length:  length,
+	}
+	for name, item := range universe.Items {
+		criticalPath.Timings[name] = StepTiming{
+			Step:          name,
+			Duration:      duration[name],
+			EarliestStart: earliestStart[name],
+			LatestStart:   computeLatestStart(item),
+		}
+	}
+	criticalPath.Path = criticalPath.follow(universe)
+
+	return criticalPath, nil
+}
+
+// Follow the critical steps from a step starting at 0, to a step ending at the length of the build
+func (c CriticalPath) follow(universe Universe) []string {
+	var path []string
+	var candidates []*Item
+	for _, item := range universe.Items {
+		if len(item.BlockedBy) == 0 {
+			candidates = append(candidates, item)
+		}
+	}
+
+	var blocks map[string][]*Item = universe.blocks()
+	for {
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+
+		var next *Item
+		for _, candidate := range candidates {
+			timing := c.Timings[candidate.Name]
+			if timing.IsCritical() && (len(path) == 0 || timing.EarliestStart == c.Timings[path[len(path)-1]].EarliestEnd()) {
+				next = candidate
+				break
+			}
+		}
+
+		if next == nil {
+			return path
+		}
+		path = append(path, next.Name)
+		candidates = blocks[next.Name]
+	}
+}
+
+// The effect of a change to the build, in elapsed time
+type WhatIf struct {
+	Before int
+	After  int
+}
+
+// The elapsed time saved by the change; negative when the build takes longer
+func (w WhatIf) Saved() int {
+	return w.Before - w.After
+}
+
+// How the elapsed time changes, with a different number of workers
+func WhatIfWorkers(universe Universe, nrWorkers, newNrWorkers int) (WhatIf, error) {
+	return whatIf(universe, nrWorkers, newNrWorkers, universe.Durations())
+}
+
+// How the elapsed time changes, when the step would take the duration instead
+func WhatIfDuration(universe Universe, nrWorkers int, step string, duration int) (WhatIf, error) {
+	durations, err := universe.durationsWith(step, duration)
+	if err != nil {
+		return WhatIf{}, err
+	}
+
+	return whatIf(universe, nrWorkers, nrWorkers, durations)
+}
+
+// How the minimum time with unlimited workers changes, when the step would take the duration instead
+func WhatIfMinimumTime(universe Universe, step string, duration int) (WhatIf, error) {
+	durations, err := universe.durationsWith(step, duration)
+	if err != nil {
+		return WhatIf{}, err
+	}
+
+	before, err := MinimumTime(universe)
+	if err != nil {
+		return WhatIf{}, err
+	}
+
+	after, err := analyseCriticalPath(universe, durations)
+	if err != nil {
+		return WhatIf{}, err
+	}
+
+	return WhatIf{Before: before, After: after.Length}, nil
+}
+
+func whatIf(universe Universe, nrWorkers, newNrWorkers int, newDurations Durations) (WhatIf, error) {
+	before, err := Build(universe.Graph, nrWorkers, universe.Durations())
+	if err != nil {
+		return WhatIf{}, err
+	}
+
+	after, err := Build(universe.Graph, newNrWorkers, newDurations)
+	if err != nil {
+		return WhatIf{}, err
+	}
+
+	return WhatIf{Before: before.Elapsed(), After: after.Elapsed()}, nil
+}
+
+// The remaining build time of every item, except for the step, which takes the duration
+func (u Universe) durationsWith(step string, duration int) (Durations, error) {
+	if _, exists := u.Items[step]; !exists {
+		return nil, fmt.Errorf("unknown step '%s'", step)
+	}
+	if duration < 0 {
+		return nil, fmt.Errorf("negative build time for step '%s': %d", step, duration)
+	}
+
+	durations := u.Durations()
+	return func(name string) (int, error) {
+		if name == step {
+			return duration, nil
+		}
+		return durations(name)
+	}, nil
+}
+
+// For every item, the items it blocks
+func (u Universe) blocks() map[string][]*Item {
+	var blocks map[string][]*Item = make(map[string][]*Item, len(u.Items))
+	for _, item := range u.Items {
+		for _, blockingItem := range item.BlockedBy {
+			blocks[blockingItem.Name] = append(blocks[blockingItem.Name], item)
+		}
+	}
+
+	return blocks
+}
@@ -0,0 +1,131 @@
+package day07thesumofitsparts
+
+import (
+	"math"
+	"slices"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/dag"
+)
+
+// A worker of a build farm
+type Worker struct {
+	Speed  float64  // How much faster than normal the worker builds; 0 counts as 1
+	Skills []string // The steps the worker can build; all steps when empty
+}
+
+// Workers that build every step at normal speed
+func IdenticalWorkers(nrWorkers int) []Worker {
+	return make([]Worker, nrWorkers)
+}
+
+func (w Worker) CanBuild(step string) bool {
+	return len(w.Skills) == 0 || slices.Contains(w.Skills, step)
+}
+
+// Speeds like 0.7 are not exact in binary, so a quotient this close to a whole number is that number
+const durationEpsilon float64 = 1e-9
+
+// How long it takes the worker to build a step of the duration, rounded up to whole seconds
+func (w Worker) Duration(duration int) int {
+	quotient := float64(duration) / w.speed()
+	if whole := math.Round(quotient); math.Abs(quotient-whole) <= durationEpsilon*max(1, whole) {
+		return int(whole)
+	}
+	return int(math.Ceil(quotient))
+}
+
+func (w Worker) speed() float64 {
+	if w.Speed <= 0 {
+		return 1
+	}
+	return w.Speed
+}
+
+// Decides which available step is started first: the step with the highest priority, and of the steps
+// with the same priority, the first in alphabetical order
+type Policy struct {
+	Name       string
+	Priorities func(graph *dag.Graph, durations Durations) (map[string]int, error)
+}
+
+var (
+	Alphabetical = Policy{
+		Name: "alphabetical",
+		Priorities: func(graph *dag.Graph, durations Durations) (map[string]int, error) {
+			return map[string]int{}, nil
+		},
+	}
+
+	LongestFirst = Policy{
+		Name: "longest first",
+		Priorities: func(graph *dag.Graph, durations Durations) (map[string]int, error) {
+			var priorities map[string]int = make(map[string]int, graph.Len())
+			for _, step := range graph.Nodes() {
+				duration, err := durations(step)
+				if err != nil {
+					return nil, err
+				}
+				priorities[step] = duration
+			}
+			return priorities, nil
+		},
+	}
+
+	// The steps blocking the most other steps, directly or indirectly
+	MostDependentsFirst = Policy{
+		Name: "most dependents first",
+		Priorities: func(graph *dag.Graph, durations Durations) (map[string]int, error) {
+			var priorities map[string]int = make(map[string]int, graph.Len())
+			for _, step := range graph.Nodes() {
+				priorities[step] = len(graph.Descendants(step))
+			}
+			return priorities, nil
+		},
+	}
+
+	// The steps with the longest chain of durations after them, including their own
+	CriticalPathFirst = Policy{
+		Name: "critical path first",
+		Priorities: func(graph *dag.Graph, durations Durations) (map[string]int, error) {
+			order, err := graph.LexicographicSort()
+			if err != nil {
+				return nil, err
+			}
+
+			var priorities map[string]int = make(map[string]int, graph.Len())
+			for idx := len(order) - 1; idx >= 0; idx-- {
+				duration, err := durations(order[idx])
+				if err != nil {
+					return nil, err
+				}
+
+				var longestAfter int = 0
+				for _, successor := range graph.Successors(order[idx]) {
+					longestAfter = max(longestAfter, priorities[successor])
+				}
+				priorities[order[idx]] = duration + longestAfter
+			}
+			return priorities, nil
+		},
+	}
+)
+
+// The elapsed time of the build per policy
+type PolicyResult struct {
+	Policy  string
+	Elapsed int
+}
+
+// Build the steps with every policy, to compare their elapsed times
+func ComparePolicies(graph *dag.Graph, workers []Worker, durations Durations, policies ...Policy) ([]PolicyResult, error) {
+	var results []PolicyResult = make([]PolicyResult, 0, len(policies))
+	for _, policy := range policies {
+		schedule, err := BuildWith(graph, workers, durations, policy)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, PolicyResult{Policy: policy.Name, Elapsed: schedule.Elapsed()})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,130 @@
+package day07thesumofitsparts
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The schedule second by second, like the listing in the puzzle:
+//
+//	Second   Worker 1   Worker 2   Done
+//	   0        C          .
+//	   3        A          F       C
+func RenderTable(w io.Writer, schedule Schedule) error {
+	var header strings.Builder
+	header.WriteString("Second")
+	for worker := 1; worker <= schedule.NrWorkers; worker++ {
+		fmt.Fprintf(&header, "   Worker %d", worker)
+	}
+	header.WriteString("   Done")
+
+	if _, err := fmt.Fprintln(w, header.String()); err != nil {
+		return err
+	}
+
+	for second := 0; second <= schedule.Elapsed(); second++ {
+		var line strings.Builder
+		fmt.Fprintf(&line, "%4d", second)
+		for worker := 1; worker <= schedule.NrWorkers; worker++ {
+			var width int = 11
+			if worker == 1 {
+				width = 9
+			}
+			fmt.Fprintf(&line, "%*s", width, schedule.stepAt(worker, second))
+		}
+		fmt.Fprintf(&line, "%7s%s", "", strings.Join(schedule.Done(second), ""))
+
+		if _, err := fmt.Fprintln(w, strings.TrimRight(line.String(), " ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// One line per task, with the columns worker, step, start and end
+func RenderCSV(w io.Writer, schedule Schedule) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"worker", "step", "start", "end"})
+	for _, task := range schedule.Tasks {
+		writer.Write([]string{strconv.Itoa(task.Worker), task.Step, strconv.Itoa(task.Start), strconv.Itoa(task.End)})
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// A Gantt chart in Mermaid notation, with a section per worker
+func RenderMermaid(w io.Writer, schedule Schedule) error {
+	var chart strings.Builder
+	chart.WriteString("gantt\n")
+	chart.WriteString("    dateFormat X\n")
+	chart.WriteString("    axisFormat %s\n")
+
+	for worker := 1; worker <= schedule.NrWorkers; worker++ {
+		fmt.Fprintf(&chart, "    section Worker %d\n", worker)
+		for _, task := range schedule.Tasks {
+			if task.Worker == worker {
+				fmt.Fprintf(&chart, "    %s : %d, %d\n", strings.NewReplacer(":", " ", "#", " ").Replace(task.Step), task.Start, task.End)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, chart.String())
+	return err
+}
+
+const (
+	SVG_ROW_HEIGHT  int = 24
+	SVG_LABEL_WIDTH int = 80
+)
+
+var SVG_COLOURS = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7"}
+
+// A Gantt chart as SVG, with a row per worker; every second takes scale pixels
+func RenderSVG(w io.Writer, schedule Schedule, scale int) error {
+	var elapsed int = schedule.Elapsed()
+	var width int = SVG_LABEL_WIDTH + elapsed*scale + SVG_ROW_HEIGHT
+	var height int = (schedule.NrWorkers + 1) * SVG_ROW_HEIGHT
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"monospace\" font-size=\"12\">\n", width, height)
+
+	for worker := 1; worker <= schedule.NrWorkers; worker++ {
+		fmt.Fprintf(&svg, "  <text x=\"4\" y=\"%d\">Worker %d</text>\n", worker*SVG_ROW_HEIGHT-8, worker)
+	}
+
+	for idx, task := range schedule.Tasks {
+		var x int = SVG_LABEL_WIDTH + task.Start*scale
+		var y int = (task.Worker-1)*SVG_ROW_HEIGHT + 2
+		var step string = html.EscapeString(task.Step)
+
+		fmt.Fprintf(&svg, "  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"><title>%s: %d-%d</title></rect>\n",
+			x, y, (task.End-task.Start)*scale, SVG_ROW_HEIGHT-4, SVG_COLOURS[idx%len(SVG_COLOURS)], step, task.Start, task.End)
+		fmt.Fprintf(&svg, "  <text x=\"%d\" y=\"%d\" fill=\"white\">%s</text>\n", x+2, y+SVG_ROW_HEIGHT-10, step)
+	}
+
+	// The time axis, with the start and the end of the build
+	var axisY int = schedule.NrWorkers*SVG_ROW_HEIGHT + 2
+	fmt.Fprintf(&svg, "  <line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"black\"/>\n", SVG_LABEL_WIDTH, axisY, SVG_LABEL_WIDTH+elapsed*scale, axisY)
+	fmt.Fprintf(&svg, "  <text x=\"%d\" y=\"%d\">0</text>\n", SVG_LABEL_WIDTH, axisY+14)
+	fmt.Fprintf(&svg, "  <text x=\"%d\" y=\"%d\">%d</text>\n", SVG_LABEL_WIDTH+elapsed*scale, axisY+14, elapsed)
+	svg.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, svg.String())
+	return err
+}
+
+// The step the worker is building during the second, or "." when the worker is idle
+func (s Schedule) stepAt(worker, second int) string {
+	for _, task := range s.Tasks {
+		if task.Worker == worker && task.Start <= second && second < task.End {
+			return task.Step
+		}
+	}
+	return "."
+}
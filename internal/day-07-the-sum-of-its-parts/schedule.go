@@ -0,0 +1,221 @@
+package day07thesumofitsparts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/dag"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/search"
+)
+
+// How long it takes to build a step
+type Durations func(step string) (int, error)
+
+// The offset plus the position of the letter in the alphabet, for steps named A to Z
+func LetterDurations(offsetBuildTime int) Durations {
+	return func(step string) (int, error) {
+		return BuildTime(step, offsetBuildTime)
+	}
+}
+
+// The durations from the table; steps not in the table are an error
+func TableDurations(table map[string]int) Durations {
+	return func(step string) (int, error) {
+		duration, exists := table[step]
+		if !exists {
+			return 0, fmt.Errorf("no build time for step '%s' in the table", step)
+		}
+		return duration, nil
+	}
+}
+
+// A step built by a worker, from its start until its end time. Workers are numbered from 1
+type Task struct {
+	Worker int
+	Step   string
+	Start  int
+	End    int
+}
+
+// The tasks of all workers, in the order they were started
+type Schedule struct {
+	NrWorkers int
+	Tasks     []Task
+}
+
+// The steps, in the order they were started
+func (s Schedule) Order() string {
+	var order strings.Builder
+	for _, task := range s.Tasks {
+		order.WriteString(task.Step)
+	}
+
+	return order.String()
+}
+
+// The time until the last step is finished
+func (s Schedule) Elapsed() int {
+	var elapsed int = 0
+	for _, task := range s.Tasks {
+		elapsed = max(elapsed, task.End)
+	}
+
+	return elapsed
+}
+
+// The time the worker spent building steps
+func (s Schedule) BusyTime(worker int) int {
+	var busy int = 0
+	for _, task := range s.Tasks {
+		if task.Worker == worker {
+			busy += task.End - task.Start
+		}
+	}
+
+	return busy
+}
+
+// The time the worker waited, until the last step was finished
+func (s Schedule) IdleTime(worker int) int {
+	return s.Elapsed() - s.BusyTime(worker)
+}
+
+// The steps finished at the time, in the order they were finished
+func (s Schedule) Done(time int) []string {
+	var finished []Task
+	for _, task := range s.Tasks {
+		if task.End <= time {
+			finished = append(finished, task)
+		}
+	}
+	slices.SortStableFunc(finished, func(a, b Task) int {
+		if a.End != b.End {
+			return a.End - b.End
+		}
+		return strings.Compare(a.Step, b.Step)
+	})
+
+	var steps []string = make([]string, 0, len(finished))
+	for _, task := range finished {
+		steps = append(steps, task.Step)
+	}
+
+	return steps
+}
+
+// Build all steps with the workers; the free worker with the lowest number starts the first available
+// step in alphabetical order. Time jumps from one finished step to the next, so it doesn't matter how
+// long the steps take
+func Build(graph *dag.Graph, nrWorkers int, durations Durations) (Schedule, error) {
+	if nrWorkers < 1 {
+		return Schedule{}, fmt.Errorf("need at least 1 worker, got %d", nrWorkers)
+	}
+
+	return BuildWith(graph, IdenticalWorkers(nrWorkers), durations, Alphabetical)
+}
+
+// Build all steps with the workers. The policy orders the available steps; every step in that order goes
+// to the fastest free worker that can build it, or waits when there is none
+func BuildWith(graph *dag.Graph, workers []Worker, durations Durations, policy Policy) (Schedule, error) {
+	if len(workers) == 0 {
+		return Schedule{}, fmt.Errorf("need at least 1 worker, got %d", len(workers))
+	}
+	if err := checkSkills(graph, workers); err != nil {
+		return Schedule{}, err
+	}
+
+	priorities, err := policy.Priorities(graph, durations)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	var nrBlocking map[string]int = make(map[string]int, graph.Len())
+	available := search.NewHeap(func(a, b string) bool {
+		return priorities[a] > priorities[b] || priorities[a] == priorities[b] && a < b
+	})
+	for _, step := range graph.Nodes() {
+		if nrBlocking[step] = len(graph.Predecessors(step)); nrBlocking[step] == 0 {
+			available.Push(step)
+		}
+	}
+
+	var isBusy []bool = make([]bool, len(workers))
+	inProgress := search.NewHeap(func(a, b Task) bool {
+		return a.End < b.End || a.End == b.End && a.Step < b.Step
+	})
+
+	var schedule Schedule = Schedule{NrWorkers: len(workers), Tasks: make([]Task, 0, graph.Len())}
+	var elapsedTime int = 0
+	for {
+		var waiting []string
+		for available.Len() > 0 && inProgress.Len() < len(workers) {
+			step := available.Pop()
+			worker := fastestFreeWorker(workers, isBusy, step)
+			if worker == -1 {
+				waiting = append(waiting, step)
+				continue
+			}
+
+			duration, err := durations(step)
+			if err != nil {
+				return Schedule{}, err
+			}
+			if duration < 0 {
+				return Schedule{}, fmt.Errorf("negative build time for step '%s': %d", step, duration)
+			}
+
+			task := Task{Worker: worker + 1, Step: step, Start: elapsedTime, End: elapsedTime + workers[worker].Duration(duration)}
+			schedule.Tasks = append(schedule.Tasks, task)
+			inProgress.Push(task)
+			isBusy[worker] = true
+		}
+		for _, step := range waiting {
+			available.Push(step)
+		}
+
+		if inProgress.Len() == 0 {
+			break
+		}
+
+		// Finish every step that ends at the same time, before the workers pick new steps
+		elapsedTime = inProgress.Peek().End
+		for inProgress.Len() > 0 && inProgress.Peek().End == elapsedTime {
+			task := inProgress.Pop()
+			isBusy[task.Worker-1] = false
+			for _, successor := range graph.Successors(task.Step) {
+				if nrBlocking[successor]--; nrBlocking[successor] == 0 {
+					available.Push(successor)
+				}
+			}
+		}
+	}
+
+	if len(schedule.Tasks) < graph.Len() {
+		return Schedule{}, &dag.CycleError{Cycle: graph.FindCycle()}
+	}
+
+	return schedule, nil
+}
+
+// The index of the fastest free worker that can build the step, or -1 when there is none
+func fastestFreeWorker(workers []Worker, isBusy []bool, step string) int {
+	var fastest int = -1
+	for idx, worker := range workers {
+		if !isBusy[idx] && worker.CanBuild(step) && (fastest == -1 || worker.speed() > workers[fastest].speed()) {
+			fastest = idx
+		}
+	}
+
+	return fastest
+}
+
+func checkSkills(graph *dag.Graph, workers []Worker) error {
+	for _, step := range graph.Nodes() {
+		if !slices.ContainsFunc(workers, func(worker Worker) bool { return worker.CanBuild(step) }) {
+			return fmt.Errorf("no worker can build step '%s'", step)
+		}
+	}
+
+	return nil
+}
@@ -1,11 +1,13 @@
 package day07thesumofitsparts
 
 import (
+	"embed"
+	"errors"
 	"fmt"
 	"regexp"
-	"sort"
 	"strings"
 
+	"github.com/ewoutquax/advent-of-code-2018/pkg/dag"
 	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
 	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 )
@@ -16,21 +18,6 @@ type Item struct {
 	Name               string
 	BlockedBy          []*Item
 	RemainingBuildTime int
-	IsInProgress       bool
-}
-
-func (item *Item) CanBeStarted() bool {
-	if item.IsInProgress || item.RemainingBuildTime == 0 {
-		return false
-	}
-
-	for _, blockingItem := range item.BlockedBy {
-		if blockingItem.RemainingBuildTime > 0 {
-			return false
-		}
-	}
-
-	return true
 }
 
 type Order struct {
@@ -41,117 +28,132 @@ type Order struct {
 type Universe struct {
 	Orders []Order
 	Items  map[string]*Item
+	Graph  *dag.Graph
 }
 
-func (u Universe) AllItemsBuild() bool {
-	for _, item := range u.Items {
-		if item.RemainingBuildTime > 0 {
-			return false
+// The remaining build time of every item
+func (u Universe) Durations() Durations {
+	return func(step string) (int, error) {
+		item, exists := u.Items[step]
+		if !exists {
+			return 0, fmt.Errorf("unknown step '%s'", step)
 		}
+		return item.RemainingBuildTime, nil
 	}
-
-	return true
 }
 
-func BuildMetrics(universe Universe, nrWorkers int) (string, int) {
-	var order []string = make([]string, 0, len(universe.Items))
-	var elapsedTime int = 0
-	var nrAvailableWorkers int = nrWorkers
+// Build the items with the workers, taking the remaining build time of every item as its duration
+func BuildMetrics(universe Universe, nrWorkers int) (Schedule, error) {
+	return Build(universe.Graph, nrWorkers, universe.Durations())
+}
 
-	for !universe.AllItemsBuild() {
-		startableItems := getStartableItems(universe)
-		for len(startableItems) > 0 && nrAvailableWorkers > 0 {
-			universe.Items[startableItems[0]].IsInProgress = true
-			order = append(order, startableItems[0])
+// Build the items with a build farm, where the policy decides which available item is started first
+func BuildMetricsWith(universe Universe, workers []Worker, policy Policy) (Schedule, error) {
+	return BuildWith(universe.Graph, workers, universe.Durations(), policy)
+}
 
-			nrAvailableWorkers--
-			startableItems = getStartableItems(universe)
-		}
+var stepExpression = regexp.MustCompile(`^Step (\S+) must be finished before step (\S+) can begin\.$`)
 
-		elapsedTime++
+// The steps, with an edge from every step to the steps that wait for it
+func ParseGraph(lines []string) (*dag.Graph, error) {
+	var graph *dag.Graph = dag.New()
 
-		for _, item := range universe.Items {
-			if item.IsInProgress {
-				item.RemainingBuildTime--
-				if item.RemainingBuildTime == 0 {
-					item.IsInProgress = false
-					nrAvailableWorkers++
-				}
-			}
+	for idx, line := range lines {
+		match := stepExpression.FindStringSubmatch(line)
+		if match == nil {
+			return nil, &utils.ParseError{Line: idx + 1, Input: line, Err: errors.New("expected 'Step X must be finished before step Y can begin.'")}
 		}
+		graph.AddEdge(match[1], match[2])
+	}
+
+	if cycle := graph.FindCycle(); cycle != nil {
+		return nil, &dag.CycleError{Cycle: cycle}
 	}
 
-	return strings.Join(order, ""), elapsedTime
+	return graph, nil
 }
 
-func getStartableItems(universe Universe) []string {
-	items := make([]string, 0, len(universe.Items))
-	for _, item := range universe.Items {
-		if item.CanBeStarted() {
-			items = append(items, item.Name)
-		}
+// The build time of a step named by a single letter: the offset plus the position of the letter in the alphabet
+func BuildTime(name string, offsetBuildTime int) (int, error) {
+	if len(name) != 1 || name[0] < 'A' || name[0] > 'Z' {
+		return 0, fmt.Errorf("no build time for step '%s': only steps named A to Z have one", name)
 	}
-	sort.Strings(items)
 
-	return items
+	return offsetBuildTime + 1 + int(name[0]-'A'), nil
 }
 
-func ParseInput(lines []string, offsetBuildTime int) Universe {
+// The steps, and the build time of every step from the durations, like LetterDurations(60)
+func ParseInput(lines []string, durations Durations) (Universe, error) {
+	graph, err := ParseGraph(lines)
+	if err != nil {
+		return Universe{}, err
+	}
+
 	var u Universe = Universe{
 		Orders: make([]Order, 0, len(lines)),
-		Items:  make(map[string]*Item, len(lines)*2),
+		Items:  make(map[string]*Item, graph.Len()),
+		Graph:  graph,
 	}
 
-	for _, line := range lines {
-		ex := regexp.MustCompile(`Step ([A-Z]) must be finished before step ([A-Z]) can begin.`)
-		match := ex.FindStringSubmatch(line)
-
-		itemBefore, existsBefore := u.Items[match[1]]
-		if !existsBefore {
-			itemBefore = &Item{
-				Name:               match[1],
-				RemainingBuildTime: offsetBuildTime + 1 + int(match[1][0]-'A'),
-			}
-		}
-
-		itemAfter, existsAfter := u.Items[match[2]]
-		if !existsAfter {
-			itemAfter = &Item{
-				Name:               match[2],
-				RemainingBuildTime: offsetBuildTime + 1 + int(match[2][0]-'A'),
-			}
+	for _, name := range graph.Nodes() {
+		buildTime, err := durations(name)
+		if err != nil {
+			return Universe{}, err
 		}
+		u.Items[name] = &Item{Name: name, RemainingBuildTime: buildTime}
+	}
 
-		u.Items[itemBefore.Name] = itemBefore
-		u.Items[itemAfter.Name] = itemAfter
-		u.Items[itemAfter.Name].BlockedBy = append(u.Items[itemAfter.Name].BlockedBy, itemBefore)
-
+	for _, edge := range graph.Edges() {
+		u.Items[edge.To].BlockedBy = append(u.Items[edge.To].BlockedBy, u.Items[edge.From])
 		u.Orders = append(u.Orders, Order{
-			Before: u.Items[itemBefore.Name],
-			After:  u.Items[itemAfter.Name],
+			Before: u.Items[edge.From],
+			After:  u.Items[edge.To],
 		})
 	}
 
-	return u
+	return u, nil
+}
+
+type solver struct {
+	lines []string
+}
+
+func (s *solver) Parse(input string) error {
+	s.lines = utils.SplitLines(input)
+	return nil
 }
 
-func solvePart1(inputFile string) {
-	lines := utils.ReadFileAsLines(inputFile)
-	universe := ParseInput(lines, 0)
-	order, _ := BuildMetrics(universe, 1)
+func (s *solver) SolvePart1() (register.Answer, error) {
+	graph, err := ParseGraph(s.lines)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := graph.LexicographicSort()
+	if err != nil {
+		return nil, err
+	}
 
-	fmt.Printf("Result of day-%s / part-1: %s\n", Day, order)
+	return register.StringAnswer(strings.Join(order, "")), nil
 }
 
-func solvePart2(inputFile string) {
-	lines := utils.ReadFileAsLines(inputFile)
-	universe := ParseInput(lines, 60)
-	_, elapsedTime := BuildMetrics(universe, 5)
+func (s *solver) SolvePart2() (register.Answer, error) {
+	graph, err := ParseGraph(s.lines)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := Build(graph, 5, LetterDurations(60))
+	if err != nil {
+		return nil, err
+	}
 
-	fmt.Printf("Result of day-%s / part-2: %d\n", Day, elapsedTime)
+	return register.IntAnswer(schedule.Elapsed()), nil
 }
 
+//go:embed *.txt
+var files embed.FS
+
 func init() {
-	register.Day(Day+"a", solvePart1)
-	register.Day(Day+"b", solvePart2)
+	register.Day(Day, files, func() register.Solver { return &solver{} })
 }
@@ -1,14 +1,22 @@
 package day07thesumofitsparts_test
 
 import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"sort"
+	"strings"
 	"testing"
 
 	. "github.com/ewoutquax/advent-of-code-2018/internal/day-07-the-sum-of-its-parts"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/dag"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestParseInput(t *testing.T) {
-	var universe = ParseInput(testInput(), 0)
+	universe, err := ParseInput(testInput(), LetterDurations(0))
+	assert.NoError(t, err)
 
 	assert := assert.New(t)
 
@@ -29,56 +37,452 @@ func TestParseInput(t *testing.T) {
 	assert.Len(universe.Items["C"].BlockedBy, 0)
 	assert.Len(universe.Items["A"].BlockedBy, 1)
 	assert.Equal("C", universe.Items["A"].BlockedBy[0].Name)
-	assert.False(universe.Items["C"].IsInProgress)
-	assert.False(universe.Items["A"].IsInProgress)
 }
 
-func TestItemCanBeStarted(t *testing.T) {
-	universe := ParseInput(testInput(), 0)
+func TestBuildMetrics(t *testing.T) {
+	universe, _ := ParseInput(testInput(), LetterDurations(0))
+	schedule, err := BuildMetrics(universe, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "CABDFE", schedule.Order())
+	assert.Equal(t, 1, schedule.NrWorkers)
+	assert.Len(t, schedule.Tasks, 6)
 
-	assert.True(t, universe.Items["C"].CanBeStarted())
+	_, err = BuildMetrics(universe, 0)
+	assert.EqualError(t, err, "need at least 1 worker, got 0")
 }
 
-func TestItemCanNotBeStartedWhenBlocked(t *testing.T) {
-	universe := ParseInput(testInput(), 0)
+func TestCalculateBuildTimeWithWorkers(t *testing.T) {
+	universe, _ := ParseInput(testInput(), LetterDurations(0))
+	schedule, err := BuildMetrics(universe, 2)
 
-	assert.False(t, universe.Items["A"].CanBeStarted())
+	assert.NoError(t, err)
+	assert.Equal(t, 15, schedule.Elapsed())
 }
 
-func TestItemCanBeStartedWhenBlockedByCompletedItems(t *testing.T) {
-	universe := ParseInput(testInput(), 0)
-	universe.Items["C"].RemainingBuildTime = 0
+func TestParseGraphWithLongNames(t *testing.T) {
+	graph, err := ParseGraph([]string{
+		"Step fetch must be finished before step compile can begin.",
+		"Step compile must be finished before step link can begin.",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fetch", "compile", "link"}, graph.Nodes())
 
-	assert.True(t, universe.Items["A"].CanBeStarted())
+	_, err = ParseInput([]string{"Step fetch must be finished before step compile can begin."}, LetterDurations(0))
+	assert.EqualError(t, err, "no build time for step 'fetch': only steps named A to Z have one")
+
+	universe, err := ParseInput([]string{"Step fetch must be finished before step compile can begin."}, TableDurations(map[string]int{"fetch": 30, "compile": 90}))
+	assert.NoError(t, err)
+	assert.Equal(t, 90, universe.Items["compile"].RemainingBuildTime)
+	assert.Equal(t, "fetch", universe.Items["compile"].BlockedBy[0].Name)
 }
 
-func TestItemCanNotBeStartedWhenAlreadyInProgress(t *testing.T) {
-	universe := ParseInput(testInput(), 0)
-	universe.Items["C"].IsInProgress = true
+func TestParseGraphWithCycle(t *testing.T) {
+	lines := append(testInput(), "Step E must be finished before step C can begin.")
 
-	assert.False(t, universe.Items["C"].CanBeStarted())
+	_, err := ParseInput(lines, LetterDurations(0))
+	assert.True(t, errors.Is(err, dag.ErrCycle))
+	assert.EqualError(t, err, "cycle: C -> A -> B -> E -> C")
 }
 
-func TestItemCanNotBeStartedWhenDone(t *testing.T) {
-	universe := ParseInput(testInput(), 0)
-	universe.Items["C"].IsInProgress = false
-	universe.Items["C"].RemainingBuildTime = 0
+func TestParseGraphWithInvalidLine(t *testing.T) {
+	_, err := ParseGraph([]string{"Step C must be finished before step A can begin.", "Step C before A"})
 
-	assert.False(t, universe.Items["C"].CanBeStarted())
+	assert.ErrorContains(t, err, "line 2")
 }
 
-func TestBuildMetrics(t *testing.T) {
-	universe := ParseInput(testInput(), 0)
-	order, _ := BuildMetrics(universe, 1)
+func TestOrderLikeSingleWorker(t *testing.T) {
+	lines := utils.ReadFileAsLines("input.txt")
+	graph, _ := ParseGraph(lines)
+	universe, _ := ParseInput(lines, LetterDurations(0))
 
-	assert.Equal(t, "CABDFE", order)
+	order, err := graph.LexicographicSort()
+	expected, _ := BuildMetrics(universe, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected.Order(), strings.Join(order, ""))
 }
 
-func TestCalculateBuildTimeWithWorkers(t *testing.T) {
-	universe := ParseInput(testInput(), 0)
-	_, elapsed := BuildMetrics(universe, 2)
+func TestBuildWithLetterDurations(t *testing.T) {
+	graph, _ := ParseGraph(testInput())
+
+	schedule, err := Build(graph, 2, LetterDurations(0))
+	assert.NoError(t, err)
+	assert.Equal(t, "CAFBDE", schedule.Order())
+	assert.Equal(t, 15, schedule.Elapsed())
+	assert.Equal(t, []Task{
+		{Worker: 1, Step: "C", Start: 0, End: 3},
+		{Worker: 1, Step: "A", Start: 3, End: 4},
+		{Worker: 2, Step: "F", Start: 3, End: 9},
+		{Worker: 1, Step: "B", Start: 4, End: 6},
+		{Worker: 1, Step: "D", Start: 6, End: 10},
+		{Worker: 1, Step: "E", Start: 10, End: 15},
+	}, schedule.Tasks)
+	assert.Equal(t, []string{"C", "A", "B", "F", "D"}, schedule.Done(10))
+	assert.Equal(t, 15, schedule.BusyTime(1))
+	assert.Equal(t, 9, schedule.IdleTime(2))
+
+	schedule, _ = Build(graph, 1, LetterDurations(0))
+	assert.Equal(t, "CABDFE", schedule.Order())
+	assert.Equal(t, 21, schedule.Elapsed())
+}
+
+func TestBuildWithTableDurations(t *testing.T) {
+	graph, _ := ParseGraph([]string{
+		"Step fetch must be finished before step compile can begin.",
+		"Step generate must be finished before step compile can begin.",
+		"Step compile must be finished before step link can begin.",
+	})
+	durations := TableDurations(map[string]int{"fetch": 30, "generate": 5, "compile": 120, "link": 10})
+
+	schedule, err := Build(graph, 2, durations)
+	assert.NoError(t, err)
+	assert.Equal(t, "fetchgeneratecompilelink", schedule.Order())
+	assert.Equal(t, 160, schedule.Elapsed())
+
+	_, err = Build(graph, 2, TableDurations(map[string]int{"fetch": 30}))
+	assert.EqualError(t, err, "no build time for step 'generate' in the table")
+
+	_, err = Build(graph, 0, durations)
+	assert.EqualError(t, err, "need at least 1 worker, got 0")
+}
+
+func TestBuildLikeTicking(t *testing.T) {
+	lines := utils.ReadFileAsLines("input.txt")
+	graph, _ := ParseGraph(lines)
+
+	for _, offset := range []int{0, 60} {
+		for nrWorkers := 1; nrWorkers <= 5; nrWorkers++ {
+			universe, _ := ParseInput(lines, LetterDurations(offset))
+			expectedOrder, expectedElapsed := buildMetricsTicking(universe, nrWorkers)
+
+			schedule, err := Build(graph, nrWorkers, LetterDurations(offset))
+			assert.NoError(t, err)
+			assert.Equal(t, expectedOrder, schedule.Order(), "offset %d, %d workers", offset, nrWorkers)
+			assert.Equal(t, expectedElapsed, schedule.Elapsed(), "offset %d, %d workers", offset, nrWorkers)
+		}
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	var buffer bytes.Buffer
+
+	assert.NoError(t, RenderTable(&buffer, exampleSchedule()))
+	assert.Equal(t, strings.Join([]string{
+		"Second   Worker 1   Worker 2   Done",
+		"   0        C          .",
+		"   1        C          .",
+		"   2        C          .",
+		"   3        A          F       C",
+		"   4        B          F       CA",
+		"   5        B          F       CA",
+		"   6        D          F       CAB",
+		"   7        D          F       CAB",
+		"   8        D          F       CAB",
+		"   9        D          .       CABF",
+		"  10        E          .       CABFD",
+		"  11        E          .       CABFD",
+		"  12        E          .       CABFD",
+		"  13        E          .       CABFD",
+		"  14        E          .       CABFD",
+		"  15        .          .       CABFDE",
+	}, "\n")+"\n", buffer.String())
+}
+
+func TestRenderCSV(t *testing.T) {
+	var buffer bytes.Buffer
+
+	assert.NoError(t, RenderCSV(&buffer, exampleSchedule()))
+	assert.Equal(t, "worker,step,start,end\n1,C,0,3\n1,A,3,4\n2,F,3,9\n1,B,4,6\n1,D,6,10\n1,E,10,15\n", buffer.String())
+}
+
+func TestRenderMermaid(t *testing.T) {
+	var buffer bytes.Buffer
+
+	assert.NoError(t, RenderMermaid(&buffer, exampleSchedule()))
+	assert.Equal(t, strings.Join([]string{
+		"gantt",
+		"    dateFormat X",
+		"    axisFormat %s",
+		"    section Worker 1",
+		"    C : 0, 3",
+		"    A : 3, 4",
+		"    B : 4, 6",
+		"    D : 6, 10",
+		"    E : 10, 15",
+		"    section Worker 2",
+		"    F : 3, 9",
+	}, "\n")+"\n", buffer.String())
+}
+
+func TestRenderSVG(t *testing.T) {
+	var buffer bytes.Buffer
+
+	assert.NoError(t, RenderSVG(&buffer, exampleSchedule(), 10))
+
+	svg := buffer.String()
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="254" height="72"`))
+	assert.Contains(t, svg, `<text x="4" y="40">Worker 2</text>`)
+	assert.Contains(t, svg, `<rect x="110" y="26" width="60" height="20" fill="#e15759"><title>F: 3-9</title></rect>`)
+	assert.Equal(t, 6, strings.Count(svg, "<rect"))
+	assert.Nil(t, xml.Unmarshal(buffer.Bytes(), new(struct{})))
+}
+
+func exampleSchedule() Schedule {
+	graph, _ := ParseGraph(testInput())
+	schedule, _ := Build(graph, 2, LetterDurations(0))
+
+	return schedule
+}
+
+func TestAnalyseCriticalPath(t *testing.T) {
+	universe, _ := ParseInput(testInput(), LetterDurations(0))
+	criticalPath, err := AnalyseCriticalPath(universe)
+
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.Equal(14, criticalPath.Length)
+	assert.Equal([]string{"C", "F", "E"}, criticalPath.Path)
+	assert.Equal(StepTiming{Step: "A", Duration: 1, EarliestStart: 3, LatestStart: 4}, criticalPath.Timings["A"])
+	assert.Equal(3, criticalPath.Timings["B"].Slack())
+	assert.Equal(1, criticalPath.Timings["D"].Slack())
+	assert.Equal(9, criticalPath.Timings["E"].EarliestStart)
+	assert.True(criticalPath.Timings["F"].IsCritical())
+	assert.Equal(9, criticalPath.Timings["F"].LatestEnd())
+}
+
+func TestAnalyseCriticalPathWithNegativeDuration(t *testing.T) {
+	universe, _ := ParseInput(testInput(), LetterDurations(0))
+	universe.Items["B"].RemainingBuildTime = -2
+
+	_, err := AnalyseCriticalPath(universe)
+	assert.EqualError(t, err, "negative build time for step 'B': -2")
+
+	_, err = MinimumTime(universe)
+	assert.EqualError(t, err, "negative build time for step 'B': -2")
+}
+
+func TestMinimumTimeLikeUnlimitedWorkers(t *testing.T) {
+	universe, _ := ParseInput(utils.ReadFileAsLines("input.txt"), LetterDurations(60))
+
+	schedule, err := Build(universe.Graph, len(universe.Items), universe.Durations())
+	assert.NoError(t, err)
+	minimumTime, err := MinimumTime(universe)
+	assert.NoError(t, err)
+	assert.Equal(t, schedule.Elapsed(), minimumTime)
+
+	criticalPath, _ := AnalyseCriticalPath(universe)
+	for _, step := range criticalPath.Path {
+		assert.True(t, criticalPath.Timings[step].IsCritical())
+	}
+}
+
+func TestWhatIf(t *testing.T) {
+	universe, _ := ParseInput(testInput(), LetterDurations(0))
+
+	whatIf, err := WhatIfWorkers(universe, 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, WhatIf{Before: 15, After: 14}, whatIf)
+	assert.Equal(t, 1, whatIf.Saved())
+
+	whatIf, _ = WhatIfDuration(universe, 2, "F", 1)
+	assert.Equal(t, WhatIf{Before: 15, After: 13}, whatIf)
+
+	whatIf, _ = WhatIfDuration(universe, 2, "B", 10)
+	assert.Equal(t, -4, whatIf.Saved())
+
+	whatIf, _ = WhatIfMinimumTime(universe, "F", 1)
+	assert.Equal(t, WhatIf{Before: 14, After: 13}, whatIf)
+
+	_, err = WhatIfDuration(universe, 2, "X", 1)
+	assert.EqualError(t, err, "unknown step 'X'")
+	_, err = WhatIfMinimumTime(universe, "A", -1)
+	assert.EqualError(t, err, "negative build time for step 'A': -1")
+}
+
+func TestComparePolicies(t *testing.T) {
+	graph, _ := ParseGraph([]string{
+		"Step Z must be finished before step Y can begin.",
+		"Step Z must be finished before step X can begin.",
+		"Step A must be finished before step END can begin.",
+		"Step B must be finished before step END can begin.",
+	})
+	durations := TableDurations(map[string]int{"A": 5, "B": 5, "Z": 1, "Y": 10, "X": 0, "END": 0})
+
+	results, err := ComparePolicies(graph, IdenticalWorkers(2), durations, Alphabetical, LongestFirst, MostDependentsFirst, CriticalPathFirst)
+	assert.NoError(t, err)
+	assert.Equal(t, []PolicyResult{
+		{Policy: "alphabetical", Elapsed: 16},
+		{Policy: "longest first", Elapsed: 16},
+		{Policy: "most dependents first", Elapsed: 15},
+		{Policy: "critical path first", Elapsed: 11},
+	}, results)
+}
+
+func TestBuildWithAlphabeticalLikeBuild(t *testing.T) {
+	universe, _ := ParseInput(utils.ReadFileAsLines("input.txt"), LetterDurations(60))
+
+	expected, _ := Build(universe.Graph, 5, universe.Durations())
+	schedule, err := BuildMetricsWith(universe, IdenticalWorkers(5), Alphabetical)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, schedule)
+}
+
+func TestBuildWithHeterogeneousWorkers(t *testing.T) {
+	graph, _ := ParseGraph(testInput())
+	workers := []Worker{{Speed: 2}, {Skills: []string{"F", "D"}}}
+
+	schedule, err := BuildWith(graph, workers, LetterDurations(0), Alphabetical)
+	assert.NoError(t, err)
+	assert.Equal(t, []Task{
+		{Worker: 1, Step: "C", Start: 0, End: 2},
+		{Worker: 1, Step: "A", Start: 2, End: 3},
+		{Worker: 2, Step: "F", Start: 2, End: 8},
+		{Worker: 1, Step: "B", Start: 3, End: 4},
+		{Worker: 1, Step: "D", Start: 4, End: 6},
+		{Worker: 1, Step: "E", Start: 8, End: 11},
+	}, schedule.Tasks)
+
+	_, err = BuildWith(graph, []Worker{{Skills: []string{"A"}}}, LetterDurations(0), Alphabetical)
+	assert.EqualError(t, err, "no worker can build step 'C'")
+
+	assert.Equal(t, 3, Worker{Speed: 1.5}.Duration(4))
+	assert.Equal(t, 4, Worker{}.Duration(4))
+}
+
+func TestWorkerDuration(t *testing.T) {
+	testCases := []struct {
+		speed    float64
+		duration int
+		expected int
+	}{
+		{0.7, 7, 10},
+		{0.1, 3, 30},
+		{0.3, 9, 30},
+		{0.7, 8, 12},
+		{1.1, 11, 10},
+		{1.1, 12, 11},
+		{3, 7, 3},
+		{0, 5, 5},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, Worker{Speed: tc.speed}.Duration(tc.duration), "%v / %v", tc.duration, tc.speed)
+	}
+}
+
+func BenchmarkParseInput(b *testing.B) {
+	lines := utils.ReadFileAsLines("input.txt")
+
+	for i := 0; i < b.N; i++ {
+		ParseInput(lines, LetterDurations(60))
+	}
+}
+
+func BenchmarkPart1(b *testing.B) {
+	lines := utils.ReadFileAsLines("input.txt")
+
+	for i := 0; i < b.N; i++ {
+		universe, _ := ParseInput(lines, LetterDurations(0))
+		BuildMetrics(universe, 1)
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	lines := utils.ReadFileAsLines("input.txt")
+
+	for i := 0; i < b.N; i++ {
+		universe, _ := ParseInput(lines, LetterDurations(60))
+		BuildMetrics(universe, 5)
+	}
+}
+
+func BenchmarkBuildLongDurations(b *testing.B) {
+	graph, _ := ParseGraph(utils.ReadFileAsLines("input.txt"))
+
+	for i := 0; i < b.N; i++ {
+		Build(graph, 5, LetterDurations(10000))
+	}
+}
+
+func BenchmarkBuildLongDurationsTicking(b *testing.B) {
+	lines := utils.ReadFileAsLines("input.txt")
+
+	for i := 0; i < b.N; i++ {
+		universe, _ := ParseInput(lines, LetterDurations(10000))
+		buildMetricsTicking(universe, 5)
+	}
+}
+
+// The original simulation, advancing one second at a time; it consumes the build times of the items
+func buildMetricsTicking(universe Universe, nrWorkers int) (string, int) {
+	var order []string = make([]string, 0, len(universe.Items))
+	var inProgress map[string]bool = make(map[string]bool, len(universe.Items))
+	var elapsedTime int = 0
+	var nrAvailableWorkers int = nrWorkers
+
+	for !allItemsBuild(universe) {
+		startableItems := getStartableItems(universe, inProgress)
+		for len(startableItems) > 0 && nrAvailableWorkers > 0 {
+			inProgress[startableItems[0]] = true
+			order = append(order, startableItems[0])
+
+			nrAvailableWorkers--
+			startableItems = getStartableItems(universe, inProgress)
+		}
+
+		elapsedTime++
+
+		for _, item := range universe.Items {
+			if inProgress[item.Name] {
+				item.RemainingBuildTime--
+				if item.RemainingBuildTime == 0 {
+					inProgress[item.Name] = false
+					nrAvailableWorkers++
+				}
+			}
+		}
+	}
+
+	return strings.Join(order, ""), elapsedTime
+}
+
+func allItemsBuild(universe Universe) bool {
+	for _, item := range universe.Items {
+		if item.RemainingBuildTime > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func getStartableItems(universe Universe, inProgress map[string]bool) []string {
+	items := make([]string, 0, len(universe.Items))
+	for _, item := range universe.Items {
+		if canBeStarted(item, inProgress) {
+			items = append(items, item.Name)
+		}
+	}
+	sort.Strings(items)
+
+	return items
+}
+
+func canBeStarted(item *Item, inProgress map[string]bool) bool {
+	if inProgress[item.Name] || item.RemainingBuildTime == 0 {
+		return false
+	}
+
+	for _, blockingItem := range item.BlockedBy {
+		if blockingItem.RemainingBuildTime > 0 {
+			return false
+		}
+	}
 
-	assert.Equal(t, 15, elapsed)
+	return true
 }
 
 func testInput() []string {
@@ -1,19 +1,19 @@
 package day13minecartmadness
 
 import (
-	"container/heap"
+	"embed"
 	"fmt"
-	"image"
-	"strings"
 
+	"github.com/ewoutquax/advent-of-code-2018/pkg/grid"
 	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/search"
 	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 )
 
 type (
-	Turn      int
-	Direction uint
-	Location  = image.Point
+	Turn      = grid.Turn
+	Direction = grid.Direction
+	Location  = grid.Point
 	Cart      struct {
 		Location Location
 		NrMoves  int
@@ -29,37 +29,27 @@ type (
 		Carts map[Location]Cart
 		Rails map[Location]*Rail
 	}
-	CartHeap []Cart
 )
 
-func (h CartHeap) Len() int { return len(h) }
-func (h CartHeap) Less(i, j int) bool {
-	return (h[i].NrMoves) < h[j].NrMoves ||
-		(h[i].NrMoves == h[j].NrMoves && h[i].Location.Y < h[j].Location.Y) ||
-		(h[i].NrMoves == h[j].NrMoves && h[i].Location.Y == h[j].Location.Y && h[i].Location.X < h[j].Location.X)
-}
-func (h CartHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
-func (h *CartHeap) Push(x any)   { *h = append(*h, x.(Cart)) }
-func (h *CartHeap) Pop() any {
-	old := *h
-	n := len(old)
-	x := old[n-1]
-	*h = old[0 : n-1]
-	return x
+// Carts move in order of their number of moves, and then from top to bottom and left to right
+func cartMovesFirst(a, b Cart) bool {
+	return a.NrMoves < b.NrMoves ||
+		(a.NrMoves == b.NrMoves && a.Location.Y < b.Location.Y) ||
+		(a.NrMoves == b.NrMoves && a.Location.Y == b.Location.Y && a.Location.X < b.Location.X)
 }
 
 const (
-	DirectionUp Direction = iota
-	DirectionRight
-	DirectionDown
-	DirectionLeft
+	DirectionUp    = grid.Up
+	DirectionRight = grid.Right
+	DirectionDown  = grid.Down
+	DirectionLeft  = grid.Left
 )
 const (
 	Day string = "13"
 
-	TurnLeft    Turn = -1
-	TurnForward Turn = 0
-	TurnRight   Turn = 1
+	TurnLeft    = grid.TurnLeft
+	TurnForward = grid.TurnForward
+	TurnRight   = grid.TurnRight
 )
 
 func (c *Cart) Move(track Track) {
@@ -89,25 +79,34 @@ func (c *Cart) Move(track Track) {
 
 	// Do we need to turn, because we're on an intersection?
 	if len(track.Rails[c.Location].Neighbours) == 4 {
-		c.Direction = (c.Direction + Direction(c.NextTurn) + 4) % 4
+		c.Direction = c.Direction.Turn(c.NextTurn)
 		c.NextTurn = getNextTurn(c.NextTurn)
 	}
 }
 
 func FindLastCrashLocation(track Track) string {
-	var cartHeap = make(CartHeap, 0, len(track.Carts))                // Priority queue that will pop the cart to move
+	location := findLastCrash(track)
+	return fmt.Sprintf("%d,%d", location.X, location.Y)
+}
+
+func FindFirstCrashLocation(track Track) string {
+	location := findFirstCrash(track)
+	return fmt.Sprintf("%d,%d", location.X, location.Y)
+}
+
+func findLastCrash(track Track) Location {
+	var cartHeap = search.NewHeap(cartMovesFirst)                     // Priority queue that will pop the cart to move
 	var cartLocations = make(map[Location]struct{}, len(track.Carts)) // Indexed list of all locations with carts, to detect crashes
 	var maxNrMoves int                                                // All carts should do the same number of moves
 
 	// Load the carts into more usable structs
 	for _, cart := range track.Carts {
-		cartHeap = append(cartHeap, cart)
+		cartHeap.Push(cart)
 		cartLocations[cart.Location] = struct{}{}
 	}
 
-	heap.Init(&cartHeap)
-	for len(cartHeap) > 1 {
-		currentCart := heap.Pop(&cartHeap).(Cart)
+	for cartHeap.Len() > 1 {
+		currentCart := cartHeap.Pop()
 
 		if _, ok := cartLocations[currentCart.Location]; ok {
 			// There is a cart on this location, which will not happen after a crash
@@ -118,7 +117,7 @@ func FindLastCrashLocation(track Track) string {
 				// There is already a cart on this location: we found a crash!
 				delete(cartLocations, currentCart.Location)
 			} else {
-				heap.Push(&cartHeap, currentCart)
+				cartHeap.Push(currentCart)
 				cartLocations[currentCart.Location] = struct{}{}
 			}
 		}
@@ -128,28 +127,27 @@ func FindLastCrashLocation(track Track) string {
 		}
 	}
 
-	lastCart := cartHeap[0]
+	lastCart := cartHeap.Peek()
 	if lastCart.NrMoves < maxNrMoves {
 		lastCart.Move(track)
 	}
 
-	return fmt.Sprintf("%d,%d", lastCart.Location.X, lastCart.Location.Y)
+	return lastCart.Location
 }
 
-func FindFirstCrashLocation(track Track) string {
-	var cartHeap = make(CartHeap, 0, len(track.Carts))                // Priority queue that will pop the cart to move
+func findFirstCrash(track Track) Location {
+	var cartHeap = search.NewHeap(cartMovesFirst)                     // Priority queue that will pop the cart to move
 	var cartLocations = make(map[Location]struct{}, len(track.Carts)) // Indexed list of all locations with carts, to detect crashes
-	var crashLocation image.Point                                     // The solution for part-1
+	var crashLocation Location                                        // The solution for part-1
 
 	// Load the carts into more usable structs
 	for _, cart := range track.Carts {
-		cartHeap = append(cartHeap, cart)
+		cartHeap.Push(cart)
 		cartLocations[cart.Location] = struct{}{}
 	}
 
-	heap.Init(&cartHeap)
-	for len(cartHeap) == len(track.Carts) {
-		currentCart := heap.Pop(&cartHeap).(Cart)
+	for cartHeap.Len() == len(track.Carts) {
+		currentCart := cartHeap.Pop()
 
 		delete(cartLocations, currentCart.Location)
 		currentCart.Move(track)
@@ -158,107 +156,97 @@ func FindFirstCrashLocation(track Track) string {
 			// There is already a cart on this location: we found a crash!
 			crashLocation = currentCart.Location
 		} else {
-			heap.Push(&cartHeap, currentCart)
+			cartHeap.Push(currentCart)
 			cartLocations[currentCart.Location] = struct{}{}
 		}
 	}
 
-	return fmt.Sprintf("%d,%d", crashLocation.X, crashLocation.Y)
+	return crashLocation
 }
 
 func ParseInput(lines []string) Track {
-	var rails = make(map[Location]*Rail)
+	var chars grid.Sparse[rune] = grid.ParseSparse(lines, func(char rune) bool { return char == ' ' })
+	var rails = make(map[Location]*Rail, len(chars))
 
 	// First, create all the rails
-	for y, line := range lines {
-		for x, char := range strings.Split(line, "") {
-			if char != " " {
-				rails[Location(image.Pt(x, y))] = &Rail{
-					Location:   Location(image.Pt(x, y)),
-					Neighbours: make(map[Direction]*Rail, 0),
-					char:       char,
-				}
-			}
+	for location, char := range chars {
+		rails[location] = &Rail{
+			Location:   location,
+			Neighbours: make(map[Direction]*Rail, 0),
+			char:       string(char),
 		}
 	}
 
 	// Now, link all the rail structs
-	for y, line := range lines {
-		for x, char := range strings.Split(line, "") {
-			currentRail := rails[Location(image.Pt(x, y))]
-
-			switch char {
-			case "-", ">", "<":
-				currentRail.Neighbours[DirectionLeft] = rails[currentRail.Location.Add(toVector(DirectionLeft))]
-				currentRail.Neighbours[DirectionRight] = rails[currentRail.Location.Add(toVector(DirectionRight))]
-			case "|", "^", "v":
-				currentRail.Neighbours[DirectionUp] = rails[currentRail.Location.Add(toVector(DirectionUp))]
-				currentRail.Neighbours[DirectionDown] = rails[currentRail.Location.Add(toVector(DirectionDown))]
-			case "+":
-				currentRail.Neighbours[DirectionUp] = rails[currentRail.Location.Add(toVector(DirectionUp))]
-				currentRail.Neighbours[DirectionRight] = rails[currentRail.Location.Add(toVector(DirectionRight))]
-				currentRail.Neighbours[DirectionDown] = rails[currentRail.Location.Add(toVector(DirectionDown))]
-				currentRail.Neighbours[DirectionLeft] = rails[currentRail.Location.Add(toVector(DirectionLeft))]
-			case "/":
-				locLeft := currentRail.Location.Add(image.Pt(-1, 0))
-				if railsLeft, ok := rails[locLeft]; ok && (railsLeft.char == "-" || railsLeft.char == "+" || railsLeft.char == "<" || railsLeft.char == ">") {
-					currentRail.Neighbours[DirectionLeft] = rails[currentRail.Location.Add(toVector(DirectionLeft))]
-					currentRail.Neighbours[DirectionUp] = rails[currentRail.Location.Add(toVector(DirectionUp))]
-				} else {
-					currentRail.Neighbours[DirectionRight] = rails[currentRail.Location.Add(toVector(DirectionRight))]
-					currentRail.Neighbours[DirectionDown] = rails[currentRail.Location.Add(toVector(DirectionDown))]
-				}
-			case `\`:
-				locLeft := currentRail.Location.Add(image.Pt(-1, 0))
-				if railsLeft, ok := rails[locLeft]; ok && (railsLeft.char == "-" || railsLeft.char == "+" || railsLeft.char == "<" || railsLeft.char == ">") {
-					currentRail.Neighbours[DirectionLeft] = rails[currentRail.Location.Add(toVector(DirectionLeft))]
-					currentRail.Neighbours[DirectionDown] = rails[currentRail.Location.Add(toVector(DirectionDown))]
-				} else {
-					currentRail.Neighbours[DirectionRight] = rails[currentRail.Location.Add(toVector(DirectionRight))]
-					currentRail.Neighbours[DirectionUp] = rails[currentRail.Location.Add(toVector(DirectionUp))]
-				}
-			case " ":
-			default:
-				panic(fmt.Sprintf("No valid case found: '%v'", char))
+	for _, currentRail := range rails {
+		switch currentRail.char {
+		case "-", ">", "<":
+			currentRail.Neighbours[DirectionLeft] = rails[currentRail.Location.Add(DirectionLeft.Vector())]
+			currentRail.Neighbours[DirectionRight] = rails[currentRail.Location.Add(DirectionRight.Vector())]
+		case "|", "^", "v":
+			currentRail.Neighbours[DirectionUp] = rails[currentRail.Location.Add(DirectionUp.Vector())]
+			currentRail.Neighbours[DirectionDown] = rails[currentRail.Location.Add(DirectionDown.Vector())]
+		case "+":
+			currentRail.Neighbours[DirectionUp] = rails[currentRail.Location.Add(DirectionUp.Vector())]
+			currentRail.Neighbours[DirectionRight] = rails[currentRail.Location.Add(DirectionRight.Vector())]
+			currentRail.Neighbours[DirectionDown] = rails[currentRail.Location.Add(DirectionDown.Vector())]
+			currentRail.Neighbours[DirectionLeft] = rails[currentRail.Location.Add(DirectionLeft.Vector())]
+		case "/":
+			locLeft := currentRail.Location.Add(DirectionLeft.Vector())
+			if railsLeft, ok := rails[locLeft]; ok && (railsLeft.char == "-" || railsLeft.char == "+" || railsLeft.char == "<" || railsLeft.char == ">") {
+				currentRail.Neighbours[DirectionLeft] = rails[currentRail.Location.Add(DirectionLeft.Vector())]
+				currentRail.Neighbours[DirectionUp] = rails[currentRail.Location.Add(DirectionUp.Vector())]
+			} else {
+				currentRail.Neighbours[DirectionRight] = rails[currentRail.Location.Add(DirectionRight.Vector())]
+				currentRail.Neighbours[DirectionDown] = rails[currentRail.Location.Add(DirectionDown.Vector())]
 			}
+		case `\`:
+			locLeft := currentRail.Location.Add(DirectionLeft.Vector())
+			if railsLeft, ok := rails[locLeft]; ok && (railsLeft.char == "-" || railsLeft.char == "+" || railsLeft.char == "<" || railsLeft.char == ">") {
+				currentRail.Neighbours[DirectionLeft] = rails[currentRail.Location.Add(DirectionLeft.Vector())]
+				currentRail.Neighbours[DirectionDown] = rails[currentRail.Location.Add(DirectionDown.Vector())]
+			} else {
+				currentRail.Neighbours[DirectionRight] = rails[currentRail.Location.Add(DirectionRight.Vector())]
+				currentRail.Neighbours[DirectionUp] = rails[currentRail.Location.Add(DirectionUp.Vector())]
+			}
+		default:
+			panic(fmt.Sprintf("No valid case found: '%v'", currentRail.char))
 		}
 	}
 
 	// Lastly, build the carts
 	var carts = make(map[Location]Cart)
-	for y, line := range lines {
-		for x, char := range strings.Split(line, "") {
-			switch char {
-			case "^":
-				carts[Location(image.Pt(x, y))] = Cart{
-					Location:  Location(image.Pt(x, y)),
-					NrMoves:   0,
-					Direction: DirectionUp,
-					NextTurn:  TurnLeft,
-				}
-			case ">":
-				carts[Location(image.Pt(x, y))] = Cart{
-					Location:  Location(image.Pt(x, y)),
-					NrMoves:   0,
-					Direction: DirectionRight,
-					NextTurn:  TurnLeft,
-				}
-			case "v":
-				carts[Location(image.Pt(x, y))] = Cart{
-					Location:  Location(image.Pt(x, y)),
-					NrMoves:   0,
-					Direction: DirectionDown,
-					NextTurn:  TurnLeft,
-				}
-			case "<":
-				carts[Location(image.Pt(x, y))] = Cart{
-					Location:  Location(image.Pt(x, y)),
-					NrMoves:   0,
-					Direction: DirectionLeft,
-					NextTurn:  TurnLeft,
-				}
-			default:
+	for location, char := range chars {
+		switch char {
+		case '^':
+			carts[location] = Cart{
+				Location:  location,
+				NrMoves:   0,
+				Direction: DirectionUp,
+				NextTurn:  TurnLeft,
+			}
+		case '>':
+			carts[location] = Cart{
+				Location:  location,
+				NrMoves:   0,
+				Direction: DirectionRight,
+				NextTurn:  TurnLeft,
+			}
+		case 'v':
+			carts[location] = Cart{
+				Location:  location,
+				NrMoves:   0,
+				Direction: DirectionDown,
+				NextTurn:  TurnLeft,
+			}
+		case '<':
+			carts[location] = Cart{
+				Location:  location,
+				NrMoves:   0,
+				Direction: DirectionLeft,
+				NextTurn:  TurnLeft,
 			}
+		default:
 		}
 	}
 
@@ -276,30 +264,28 @@ func getNextTurn(t Turn) Turn {
 	}[t]
 }
 
-func toVector(d Direction) image.Point {
-	return map[Direction]image.Point{
-		DirectionUp:    image.Pt(0, -1),
-		DirectionRight: image.Pt(1, 0),
-		DirectionDown:  image.Pt(0, 1),
-		DirectionLeft:  image.Pt(-1, 0),
-	}[d]
+type solver struct {
+	track Track
 }
 
-func solvePart1(inputFile string) {
-	lines := utils.ReadFileAsLines(inputFile)
-	track := ParseInput(lines)
-
-	fmt.Printf("Result of day-%s / part-1: %s\n", Day, FindFirstCrashLocation(track))
+func (s *solver) Parse(input string) error {
+	s.track = ParseInput(utils.SplitLines(input))
+	return nil
 }
 
-func solvePart2(inputFile string) {
-	lines := utils.ReadFileAsLines(inputFile)
-	track := ParseInput(lines)
+func (s *solver) SolvePart1() (register.Answer, error) {
+	location := findFirstCrash(s.track)
+	return register.CoordinateAnswer{X: location.X, Y: location.Y}, nil
+}
 
-	fmt.Printf("Result of day-%s / part-2: %s\n", Day, FindLastCrashLocation(track))
+func (s *solver) SolvePart2() (register.Answer, error) {
+	location := findLastCrash(s.track)
+	return register.CoordinateAnswer{X: location.X, Y: location.Y}, nil
 }
 
+//go:embed *.txt
+var files embed.FS
+
 func init() {
-	register.Day(Day+"a", solvePart1)
-	register.Day(Day+"b", solvePart2)
+	register.Day(Day, files, func() register.Solver { return &solver{} })
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	. "github.com/ewoutquax/advent-of-code-2018/internal/day-13-mine-cart-madness"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -75,6 +76,30 @@ func TestFindLastCrashLocation(t *testing.T) {
 	assert.Equal(t, "6,4", FindLastCrashLocation(track))
 }
 
+func BenchmarkParseInput(b *testing.B) {
+	lines := utils.ReadFileAsLines("input.txt")
+
+	for i := 0; i < b.N; i++ {
+		ParseInput(lines)
+	}
+}
+
+func BenchmarkPart1(b *testing.B) {
+	track := ParseInput(utils.ReadFileAsLines("input.txt"))
+
+	for i := 0; i < b.N; i++ {
+		FindFirstCrashLocation(track)
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	track := ParseInput(utils.ReadFileAsLines("input.txt"))
+
+	for i := 0; i < b.N; i++ {
+		FindLastCrashLocation(track)
+	}
+}
+
 func testInput() []string {
 	return []string{
 		`/->-\`,
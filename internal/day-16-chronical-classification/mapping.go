@@ -0,0 +1,173 @@
+package day16chronicalclassification
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/elfcode"
+)
+
+const NR_OPCODES int = 16
+
+var (
+	ErrContradictingSamples = errors.New("samples contradict each other")
+	ErrAmbiguousMapping     = errors.New("samples don't determine a single mapping")
+)
+
+// For every opcode number, the opcodes that are valid for all its samples.
+// Numbers without samples can still be any opcode
+type CandidateMatrix map[int][]Opcode
+
+// The failure to map the opcode numbers, with the candidates that remained
+type MappingError struct {
+	Err        error
+	Candidates CandidateMatrix
+}
+
+func (e *MappingError) Error() string {
+	return fmt.Sprintf("map opcodes: %v; candidates:\n%s", e.Err, e.Candidates)
+}
+
+func (e *MappingError) Unwrap() error {
+	return e.Err
+}
+
+func BuildCandidates(samples []Sample) CandidateMatrix {
+	var candidates CandidateMatrix = make(CandidateMatrix, NR_OPCODES)
+
+	for nr := 0; nr < NR_OPCODES; nr++ {
+		candidates[nr] = elfcode.AllOpcodes()
+	}
+
+	for _, sample := range samples {
+		nr := int(sample.Instruction.Opcode)
+		candidates[nr] = slices.DeleteFunc(candidates[nr], func(opcode Opcode) bool {
+			return !sample.IsValidFor(opcode)
+		})
+	}
+
+	return candidates
+}
+
+// The candidates per opcode number, one number per line, like "9: addi, mulr, seti"
+func (m CandidateMatrix) String() string {
+	var lines []string = make([]string, 0, len(m))
+
+	for _, nr := range m.numbers() {
+		var names []string = make([]string, 0, len(m[nr]))
+		for _, opcode := range m[nr] {
+			names = append(names, opcode.String())
+		}
+		if len(names) == 0 {
+			names = append(names, "-")
+		}
+		lines = append(lines, fmt.Sprintf("%2d: %s", nr, strings.Join(names, ", ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m CandidateMatrix) Clone() CandidateMatrix {
+	var clone CandidateMatrix = make(CandidateMatrix, len(m))
+
+	for nr, opcodes := range m {
+		clone[nr] = slices.Clone(opcodes)
+	}
+
+	return clone
+}
+
+func (m CandidateMatrix) numbers() []int {
+	var numbers []int = make([]int, 0, len(m))
+
+	for nr := range m {
+		numbers = append(numbers, nr)
+	}
+	slices.Sort(numbers)
+
+	return numbers
+}
+
+// Map every opcode number to its opcode, as determined by the samples
+func MapOpcodes(samples []Sample) (MappingOpcode, error) {
+	return SolveMapping(BuildCandidates(samples))
+}
+
+// Find the single mapping in which every number gets a different opcode out of its candidates.
+// Elimination settles most numbers; backtracking settles the rest, and proves the mapping is unique
+func SolveMapping(candidates CandidateMatrix) (MappingOpcode, error) {
+	var reduced CandidateMatrix = candidates.Clone()
+
+	if !reduced.eliminate() {
+		return nil, &MappingError{Err: ErrContradictingSamples, Candidates: reduced}
+	}
+
+	var solutions []MappingOpcode = make([]MappingOpcode, 0, 2)
+	reduced.backtrack(reduced.numbers(), make(MappingOpcode, len(reduced)), make(map[Opcode]bool, len(reduced)), &solutions)
+
+	switch len(solutions) {
+	case 0:
+		return nil, &MappingError{Err: ErrContradictingSamples, Candidates: reduced}
+	case 1:
+		return solutions[0], nil
+	default:
+		return nil, &MappingError{Err: ErrAmbiguousMapping, Candidates: reduced}
+	}
+}
+
+// Remove the opcode of every number with a single candidate from all other numbers, until nothing changes.
+// Returns false when a number runs out of candidates
+func (m CandidateMatrix) eliminate() bool {
+	var settled map[int]bool = make(map[int]bool, len(m))
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, nr := range m.numbers() {
+			if len(m[nr]) == 0 {
+				return false
+			}
+			if len(m[nr]) > 1 || settled[nr] {
+				continue
+			}
+
+			settled[nr] = true
+			changed = true
+			for otherNr := range m {
+				if otherNr != nr {
+					m[otherNr] = slices.DeleteFunc(m[otherNr], func(opcode Opcode) bool { return opcode == m[nr][0] })
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// Try every remaining candidate for the numbers, stopping after finding a second solution
+func (m CandidateMatrix) backtrack(numbers []int, mapping MappingOpcode, used map[Opcode]bool, solutions *[]MappingOpcode) {
+	if len(*solutions) > 1 {
+		return
+	}
+
+	if len(numbers) == 0 {
+		*solutions = append(*solutions, maps.Clone(mapping))
+		return
+	}
+
+	nr := numbers[0]
+	for _, opcode := range m[nr] {
+		if used[opcode] {
+			continue
+		}
+
+		mapping[nr] = opcode
+		used[opcode] = true
+		m.backtrack(numbers[1:], mapping, used, solutions)
+		delete(mapping, nr)
+		delete(used, opcode)
+	}
+}
@@ -1,119 +1,84 @@
 package day16chronicalclassification
 
 import (
+	"embed"
+	"errors"
 	"fmt"
+	"io"
 	"slices"
-	"sort"
-	"strconv"
 	"strings"
 
+	"github.com/ewoutquax/advent-of-code-2018/pkg/elfcode"
 	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
 	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 )
 
-type Opcode uint
+type (
+	Opcode      = elfcode.Opcode
+	Instruction = elfcode.Instruction
+	Registers   elfcode.Registers
+)
 
 const (
-	Addr Opcode = iota + 1
-	Addi
-	Muli
-	Mulr
-	Banr
-	Bani
-	Borr
-	Bori
-	Setr
-	Seti
-	Gtir
-	Gtri
-	Gtrr
-	Eqir
-	Eqri
-	Eqrr
-
-	MIN_VALID_OPCODES int    = 3
-	Day               string = "16"
+	Addr = elfcode.Addr
+	Addi = elfcode.Addi
+	Muli = elfcode.Muli
+	Mulr = elfcode.Mulr
+	Banr = elfcode.Banr
+	Bani = elfcode.Bani
+	Borr = elfcode.Borr
+	Bori = elfcode.Bori
+	Setr = elfcode.Setr
+	Seti = elfcode.Seti
+	Gtir = elfcode.Gtir
+	Gtri = elfcode.Gtri
+	Gtrr = elfcode.Gtrr
+	Eqir = elfcode.Eqir
+	Eqri = elfcode.Eqri
+	Eqrr = elfcode.Eqrr
+
+	MIN_VALID_OPCODES    int    = 3
+	DEFAULT_NR_REGISTERS int    = 4
+	Day                  string = "16"
 )
 
 type MappingOpcode map[int]Opcode
 
-type Instruction struct {
-	Opcode
-	InputA int
-	InputB int
-	Output int
+func (r Registers) ToS() string {
+	return elfcode.Registers(r).String()
 }
 
-type Registers map[int]int
+// A sample of the manual: the registers before and after executing an instruction with an unknown opcode
+type Sample struct {
+	Before      Registers
+	Instruction Instruction
+	After       Registers
+}
 
-func (r Registers) ToS() string {
-	return fmt.Sprintf("[%d, %d, %d, %d]", r[0], r[1], r[2], r[3])
+// The opcodes that match the sample in the 3 lines
+func ValidOpcodes(lines []string) ([]Opcode, error) {
+	sample, err := parseSample(lines, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return sample.ValidOpcodes(), nil
 }
 
-func (i Instruction) Exec(registers Registers) {
-	valueTesting := 0
-
-	switch i.Opcode {
-	case Addr:
-		registers[i.Output] = registers[i.InputA] + registers[i.InputB]
-	case Addi:
-		registers[i.Output] = registers[i.InputA] + i.InputB
-	case Mulr:
-		registers[i.Output] = registers[i.InputA] * registers[i.InputB]
-	case Muli:
-		registers[i.Output] = registers[i.InputA] * i.InputB
-	case Banr:
-		registers[i.Output] = registers[i.InputA] & registers[i.InputB]
-	case Bani:
-		registers[i.Output] = registers[i.InputA] & i.InputB
-	case Borr:
-		registers[i.Output] = registers[i.InputA] | registers[i.InputB]
-	case Bori:
-		registers[i.Output] = registers[i.InputA] | i.InputB
-	case Setr:
-		registers[i.Output] = registers[i.InputA]
-	case Seti:
-		registers[i.Output] = i.InputA
-	case Gtir:
-		if i.InputA > registers[i.InputB] {
-			valueTesting = 1
-		}
-		registers[i.Output] = valueTesting
-	case Gtri:
-		if registers[i.InputA] > i.InputB {
-			valueTesting = 1
-		}
-		registers[i.Output] = valueTesting
-	case Gtrr:
-		if registers[i.InputA] > registers[i.InputB] {
-			valueTesting = 1
-		}
-		registers[i.Output] = valueTesting
-	case Eqir:
-		if i.InputA == registers[i.InputB] {
-			valueTesting = 1
-		}
-		registers[i.Output] = valueTesting
-	case Eqri:
-		if registers[i.InputA] == i.InputB {
-			valueTesting = 1
-		}
-		registers[i.Output] = valueTesting
-	case Eqrr:
-		if registers[i.InputA] == registers[i.InputB] {
-			valueTesting = 1
-		}
-		registers[i.Output] = valueTesting
-	default:
-		panic("No valid case found")
+func IsOpcodeValidForBlock(opcode Opcode, lines []string) (bool, error) {
+	sample, err := parseSample(lines, 1)
+	if err != nil {
+		return false, err
 	}
+
+	return sample.IsValidFor(opcode), nil
 }
 
-func ValidOpcodes(lines []string) []Opcode {
-	var validOpcodes []Opcode = make([]Opcode, 0, len(allOpcodes()))
+func (s Sample) ValidOpcodes() []Opcode {
+	var validOpcodes []Opcode = make([]Opcode, 0, len(elfcode.AllOpcodes()))
 
-	for _, currentOpcode := range allOpcodes() {
-		if IsOpcodeValidForBlock(currentOpcode, lines) {
+	for _, currentOpcode := range elfcode.AllOpcodes() {
+		if s.IsValidFor(currentOpcode) {
 			validOpcodes = append(validOpcodes, currentOpcode)
 		}
 	}
@@ -121,168 +86,238 @@ func ValidOpcodes(lines []string) []Opcode {
 	return validOpcodes
 }
 
-func IsOpcodeValidForBlock(opcode Opcode, lines []string) bool {
-	var registers Registers = SetRegisters(lines[0])
+func (s Sample) IsValidFor(opcode Opcode) bool {
+	var registers elfcode.Registers = slices.Clone(elfcode.Registers(s.Before))
 
-	instruction := ParseInstruction(lines[1])
+	instruction := s.Instruction
 	instruction.Opcode = opcode
-	instruction.Exec(registers)
+	if err := instruction.Exec(registers); err != nil {
+		// The instruction refers to a register the device doesn't have
+		return false
+	}
 
-	return fmt.Sprintf("After:  %s", registers.ToS()) == lines[2]
+	return slices.Equal(registers, elfcode.Registers(s.After))
 }
 
-func SetRegisters(line string) Registers {
-	var registers Registers = make(Registers, 4)
+func SetRegisters(line string) (Registers, error) {
+	return parseRegisters(line, 0, 0)
+}
 
-	suffix := strings.Split(line[:len(line)-1], "[")[1]
-	parts := strings.Split(suffix, ", ")
+func ParseInstruction(line string) (Instruction, error) {
+	return parseInstruction(line, 0)
+}
 
-	registers[0] = convAtoI(parts[0])
-	registers[1] = convAtoI(parts[1])
-	registers[2] = convAtoI(parts[2])
-	registers[3] = convAtoI(parts[3])
+// Parse the samples, followed by the test program.
+// Errors point to the offending line, counting from 1
+func ParseInput(lines []string) ([]Sample, []Instruction, error) {
+	var samples []Sample = make([]Sample, 0, len(lines)/4)
+	var program []Instruction = make([]Instruction, 0, len(lines))
+	var idx int = 0
 
-	return registers
-}
+	for idx < len(lines) && strings.HasPrefix(lines[idx], "Before:") {
+		if idx+2 >= len(lines) {
+			return nil, nil, &utils.ParseError{Line: idx + 1, Input: lines[idx], Err: errors.New("incomplete sample")}
+		}
 
-func ParseInstruction(line string) Instruction {
-	parts := strings.Split(line, " ")
+		sample, err := parseSample(lines[idx:idx+3], idx+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		samples = append(samples, sample)
 
-	return Instruction{
-		Opcode: Opcode(convAtoI(parts[0])),
-		InputA: convAtoI(parts[1]),
-		InputB: convAtoI(parts[2]),
-		Output: convAtoI(parts[3]),
+		for idx += 3; idx < len(lines) && lines[idx] == ""; idx++ {
+		}
 	}
+
+	for ; idx < len(lines); idx++ {
+		if lines[idx] == "" {
+			continue
+		}
+
+		instruction, err := parseInstruction(lines[idx], idx+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		program = append(program, instruction)
+	}
+
+	return samples, program, nil
 }
 
-func convAtoI(s string) int {
-	nr, err := strconv.Atoi(s)
+// Parse the 3 lines of a sample, of which the first line has the given line number
+func parseSample(lines []string, lineNr int) (Sample, error) {
+	if !strings.HasPrefix(lines[0], "Before:") {
+		return Sample{}, &utils.ParseError{Line: lineNr, Input: lines[0], Err: errors.New("expected 'Before: [...]'")}
+	}
+	if !strings.HasPrefix(lines[2], "After:") {
+		return Sample{}, &utils.ParseError{Line: lineNr + 2, Input: lines[2], Err: errors.New("expected 'After: [...]'")}
+	}
+
+	before, err := parseRegisters(lines[0], lineNr, 0)
+	if err != nil {
+		return Sample{}, err
+	}
+	instruction, err := parseInstruction(lines[1], lineNr+1)
 	if err != nil {
-		panic(err)
+		return Sample{}, err
 	}
-	return nr
+	after, err := parseRegisters(lines[2], lineNr+2, len(before))
+	if err != nil {
+		return Sample{}, err
+	}
+
+	return Sample{Before: before, Instruction: instruction, After: after}, nil
 }
 
-func allOpcodes() []Opcode {
-	return []Opcode{
-		Addr, Addi,
-		Muli, Mulr,
-		Banr, Bani,
-		Borr, Bori,
-		Setr, Seti,
-		Gtir, Gtri, Gtrr,
-		Eqir, Eqri, Eqrr,
+// Parse registers like "Before: [3, 2, 1, 1]". Any number of registers is accepted, unless nrRegisters is set
+func parseRegisters(line string, lineNr int, nrRegisters int) (Registers, error) {
+	start := strings.Index(line, "[")
+	end := strings.LastIndex(line, "]")
+	if start == -1 || end < start {
+		return nil, &utils.ParseError{Line: lineNr, Input: line, Err: errors.New("expected registers like '[3, 2, 1, 1]'")}
 	}
-}
 
-func solvePart1(inputFile string) {
-	blocks := utils.ReadFileAsBlocks(inputFile)
+	parts := strings.Split(line[start+1:end], ", ")
+	if nrRegisters > 0 && len(parts) != nrRegisters {
+		return nil, &utils.ParseError{Line: lineNr, Input: line, Err: fmt.Errorf("expected %d registers, got %d", nrRegisters, len(parts))}
+	}
 
-	var count int = 0
+	var registers Registers = Registers(elfcode.NewRegisters(len(parts)))
+	var column int = start + 2
+	for idx, part := range parts {
+		value, err := utils.ParseIntAt(part, lineNr, column)
+		if err != nil {
+			return nil, err
+		}
+		registers[idx] = value
+		column += len(part) + len(", ")
+	}
 
-	for idx := 0; idx < len(blocks)-2; idx++ {
-		validOpcodes := ValidOpcodes(blocks[idx])
-		if len(validOpcodes) >= MIN_VALID_OPCODES {
-			count++
+	return registers, nil
+}
+
+// Parse an instruction like "9 2 1 2", starting with the number of its opcode
+func parseInstruction(line string, lineNr int) (Instruction, error) {
+	parts := strings.Split(line, " ")
+	if len(parts) != 4 {
+		return Instruction{}, &utils.ParseError{Line: lineNr, Input: line, Err: fmt.Errorf("expected 4 numbers, got %d", len(parts))}
+	}
+
+	var values [4]int
+	var column int = 1
+	for idx, part := range parts {
+		value, err := utils.ParseIntAt(part, lineNr, column)
+		if err != nil {
+			return Instruction{}, err
 		}
+		if idx == 0 && (value < 0 || value >= NR_OPCODES) {
+			return Instruction{}, &utils.ParseError{Line: lineNr, Column: column, Input: part, Err: fmt.Errorf("expected an opcode number from 0 to %d", NR_OPCODES-1)}
+		}
+		values[idx] = value
+		column += len(part) + 1
 	}
 
-	fmt.Printf("Result of day-%s / part-1: %d\n", Day, count)
+	return Instruction{
+		Opcode: Opcode(values[0]),
+		InputA: values[1],
+		InputB: values[2],
+		Output: values[3],
+	}, nil
 }
 
-func solvePart2(inputFile string) {
-	blocks := utils.ReadFileAsBlocks(inputFile)
+type solver struct {
+	samples []Sample
+	program []Instruction
+	trace   io.Writer
+}
 
-	mappedOpcodes := mapOpcodes(blocks)
+// Write the disassembly of the program of part 2, and every instruction it executes, to w
+func (s *solver) SetTrace(w io.Writer) {
+	s.trace = w
+}
 
-	var registers Registers = Registers{
-		0: 0,
-		1: 0,
-		2: 0,
-		3: 0,
-	}
+func (s *solver) Parse(input string) (err error) {
+	s.samples, s.program, err = ParseInput(utils.SplitLines(input))
+	return
+}
 
-	for _, rawInstruction := range blocks[len(blocks)-1] {
-		instruction := ParseInstruction(rawInstruction)
-		instruction.Opcode = mappedOpcodes[int(instruction.Opcode)]
+func (s *solver) SolvePart1() (register.Answer, error) {
+	var count int = 0
 
-		instruction.Exec(registers)
+	for _, sample := range s.samples {
+		if len(sample.ValidOpcodes()) >= MIN_VALID_OPCODES {
+			count++
+		}
 	}
 
-	fmt.Printf("Result of day-%s / part-2: %d\n", Day, registers[0])
+	return register.IntAnswer(count), nil
 }
 
-func mapOpcodes(blocks [][]string) MappingOpcode {
-	type OpcodeToInts map[Opcode][]int
+func (s *solver) SolvePart2() (register.Answer, error) {
+	mappedOpcodes, err := MapOpcodes(s.samples)
+	if err != nil {
+		return nil, err
+	}
 
-	var opcodeToInts OpcodeToInts = make(OpcodeToInts)
+	// The device has as many registers as the samples show
+	var nrRegisters int = DEFAULT_NR_REGISTERS
+	if len(s.samples) > 0 {
+		nrRegisters = len(s.samples[0].Before)
+	}
 
-	// initialize
-	for idx := 0; idx <= 15; idx++ {
-		opcodeToInts[Opcode(idx+1)] = make([]int, 0, 16)
+	if s.trace != nil {
+		fmt.Fprintln(s.trace, strings.Join(Disassemble(s.program, mappedOpcodes), "\n"))
 	}
 
-	// find valid Opcode by opcode-id
-	for idx := 0; idx < len(blocks)-2; idx++ {
-		instruction := ParseInstruction(blocks[idx][1])
+	registers, err := TraceProgram(s.program, mappedOpcodes, nrRegisters, s.trace)
+	if err != nil {
+		return nil, err
+	}
 
-		opcodes := ValidOpcodes(blocks[idx])
+	return register.IntAnswer(registers[0]), nil
+}
 
-		// store possible opcode-ids by Opcode
-		for _, opcode := range opcodes {
-			opcodeToInts[opcode] = append(opcodeToInts[opcode], int(instruction.Opcode))
-		}
-	}
+// Translate the opcode numbers of the program with the mapping, and run it from empty registers
+func RunProgram(program []Instruction, mapping MappingOpcode, nrRegisters int) (Registers, error) {
+	return TraceProgram(program, mapping, nrRegisters, nil)
+}
 
-	// remove duplicate opcode-ids per opcode, and sort them for convinience
-	for opcode, ints := range opcodeToInts {
-		uniqInts := uniq(ints)
-		sort.Ints(uniqInts)
-		opcodeToInts[opcode] = uniqInts
+// Run the program like RunProgram, writing every executed instruction with its registers to w, when not nil
+func TraceProgram(program []Instruction, mapping MappingOpcode, nrRegisters int, w io.Writer) (Registers, error) {
+	machine := elfcode.NewMachine(Translate(program, mapping), nrRegisters)
+	if w != nil {
+		machine.Tracer = elfcode.WriteTrace(w)
 	}
 
-	var mappedOpcodes map[int]Opcode = make(map[int]Opcode, 16)
-
-	// When an opcode is linked to just 1 opcode-id, then copy the link to the output and remove the opcode-id from all opcodes
-	for len(mappedOpcodes) < 16 {
-		for opcode, ints := range opcodeToInts {
-			if len(ints) == 1 {
-				mappedOpcodes[ints[0]] = opcode
-
-				for subOpcode, subInts := range opcodeToInts {
-					var cleanedInts []int = make([]int, 0)
-					if len(subInts) > 0 {
-						cleanedInts = slices.DeleteFunc(subInts, func(subInt int) bool {
-							return subInt == ints[0]
-						})
-					}
-
-					opcodeToInts[subOpcode] = cleanedInts
-				}
-			}
-		}
+	if err := machine.Run(); err != nil {
+		return nil, err
 	}
 
-	return mappedOpcodes
+	return Registers(machine.Registers), nil
 }
 
-func uniq(ints []int) []int {
-	var uniqInts []int = make([]int, 0, 15)
-	var unique map[int]bool = make(map[int]bool, 15)
+// The program as mnemonic assembly, one numbered instruction per line
+func Disassemble(program []Instruction, mapping MappingOpcode) []string {
+	return Translate(program, mapping).Disassemble()
+}
 
-	for _, currentInt := range ints {
-		unique[currentInt] = true
+// The program with its opcode numbers replaced by the mapped opcodes
+func Translate(program []Instruction, mapping MappingOpcode) elfcode.Program {
+	var translated elfcode.Program = elfcode.Program{
+		Instructions: make([]Instruction, 0, len(program)),
 	}
 
-	for currentInt := range unique {
-		uniqInts = append(uniqInts, currentInt)
+	for _, instruction := range program {
+		instruction.Opcode = mapping[int(instruction.Opcode)]
+		translated.Instructions = append(translated.Instructions, instruction)
 	}
 
-	return uniqInts
+	return translated
 }
 
+//go:embed *.txt
+var files embed.FS
+
 func init() {
-	register.Day(Day+"b", solvePart2)
-	register.Day(Day+"a", solvePart1)
+	register.Day(Day, files, func() register.Solver { return &solver{} })
 }
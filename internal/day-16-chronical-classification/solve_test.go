@@ -2,16 +2,22 @@ package day16chronicalclassification_test
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
 
 	. "github.com/ewoutquax/advent-of-code-2018/internal/day-16-chronical-classification"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/elfcode"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestSetRegisters(t *testing.T) {
 	assert := assert.New(t)
-	registers := SetRegisters(testInput()[0])
+	registers, err := SetRegisters(testInput()[0])
 
+	assert.NoError(err)
 	assert.IsType(Registers{}, registers)
 	assert.Equal(3, registers[0])
 	assert.Equal(2, registers[1])
@@ -23,8 +29,9 @@ func TestParseInstruction(t *testing.T) {
 	assert := assert.New(t)
 
 	line := testInput()[1]
-	instruction := ParseInstruction(line)
+	instruction, err := ParseInstruction(line)
 
+	assert.NoError(err)
 	assert.IsType(Instruction{}, instruction)
 	assert.Equal(2, instruction.InputA)
 	assert.Equal(1, instruction.InputB)
@@ -42,10 +49,48 @@ func TestRegistersToS(t *testing.T) {
 	assert.Equal(t, "[9, 8, 7, 6]", registers.ToS())
 }
 
+func TestSixRegisters(t *testing.T) {
+	registers, _ := SetRegisters("Before: [3, 2, 1, 1, 0, 5]")
+	assert.Equal(t, "[3, 2, 1, 1, 0, 5]", registers.ToS())
+
+	validOpcodes, _ := ValidOpcodes([]string{
+		"Before: [3, 2, 1, 1, 0, 5]",
+		"9 5 1 4",
+		"After:  [3, 2, 1, 1, 5, 5]",
+	})
+	assert.Equal(t, []Opcode{Muli, Bori, Setr, Seti}, validOpcodes)
+}
+
+func TestValidOpcodesInvalidRegister(t *testing.T) {
+	// With 4 registers, the output register 4 doesn't exist
+	validOpcodes, err := ValidOpcodes([]string{
+		"Before: [3, 2, 1, 1]",
+		"9 2 1 4",
+		"After:  [3, 2, 1, 1]",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, validOpcodes)
+}
+
+func TestParseHelpersWithInvalidInput(t *testing.T) {
+	_, err := SetRegisters("Before: 3, 2, 1, 1")
+	assert.ErrorContains(t, err, "expected registers like '[3, 2, 1, 1]'")
+
+	_, err = ParseInstruction("9 2 1")
+	assert.ErrorContains(t, err, "expected 4 numbers, got 3")
+
+	_, err = ValidOpcodes([]string{"Before: [3, 2, 1, 1]", "9 2 x 2", "After:  [3, 2, 2, 1]"})
+	assert.ErrorContains(t, err, "line 2, column 5")
+
+	_, err = IsOpcodeValidForBlock(Addi, []string{"Before: [3, 2, 1, 1]", "9 2 1 2", "[3, 2, 2, 1]"})
+	assert.ErrorContains(t, err, "expected 'After: [...]'")
+}
+
 func TestValidOpcodes(t *testing.T) {
 	assert := assert.New(t)
-	validOpcodes := ValidOpcodes(testInput())
+	validOpcodes, err := ValidOpcodes(testInput())
 
+	assert.NoError(err)
 	assert.Len(validOpcodes, 3)
 	assert.Contains(validOpcodes, Mulr)
 	assert.Contains(validOpcodes, Addi)
@@ -83,10 +128,278 @@ func TestBorr(t *testing.T) {
 		"After:  [3, 3, 4, 0]",
 	}
 
-	assert.True(t, IsOpcodeValidForBlock(Bori, blockBori))
-	assert.True(t, IsOpcodeValidForBlock(Borr, blockBorr))
-	assert.True(t, IsOpcodeValidForBlock(Bani, blockBani))
-	assert.True(t, IsOpcodeValidForBlock(Banr, blockBanr))
+	isValid := func(opcode Opcode, lines []string) bool {
+		valid, err := IsOpcodeValidForBlock(opcode, lines)
+		assert.NoError(t, err)
+		return valid
+	}
+
+	assert.True(t, isValid(Bori, blockBori))
+	assert.True(t, isValid(Borr, blockBorr))
+	assert.True(t, isValid(Bani, blockBani))
+	assert.True(t, isValid(Banr, blockBanr))
+}
+
+func TestParseInput(t *testing.T) {
+	assert := assert.New(t)
+
+	lines := append(testInput(), "", "Before: [0, 0, 0, 0]", "9 0 7 1", "After:  [0, 7, 0, 0]", "", "", "", "9 0 5 3", "9 3 1 0")
+	samples, program, err := ParseInput(lines)
+
+	assert.NoError(err)
+	assert.Len(samples, 2)
+	assert.Equal(Registers{0: 3, 1: 2, 2: 1, 3: 1}, samples[0].Before)
+	assert.Equal(Registers{0: 0, 1: 7, 2: 0, 3: 0}, samples[1].After)
+	assert.Equal(7, samples[1].Instruction.InputB)
+	assert.Len(program, 2)
+	assert.Equal(Instruction{Opcode: 9, InputA: 3, InputB: 1, Output: 0}, program[1])
+}
+
+func TestParseInputInvalid(t *testing.T) {
+	testCases := map[string][]string{
+		"line 2, column 3: parse 'x': invalid syntax":                          {"Before: [3, 2, 1, 1]", "9 x 1 2", "After:  [3, 2, 2, 1]"},
+		"line 3, column 19: parse '1]x': invalid syntax":                       {"Before: [3, 2, 1, 1]", "9 2 1 2", "After:  [3, 2, 2, 1]x]"},
+		"line 3: parse 'After:  [3, 2, 2]': expected 4 registers, got 3":       {"Before: [3, 2, 1, 1]", "9 2 1 2", "After:  [3, 2, 2]"},
+		"line 3: parse 'Afetr:  [3, 2, 2, 1]': expected 'After: [...]'":        {"Before: [3, 2, 1, 1]", "9 2 1 2", "Afetr:  [3, 2, 2, 1]"},
+		"line 1: parse 'Before: [3, 2, 1, 1]': incomplete sample":              {"Before: [3, 2, 1, 1]", "9 2 1 2"},
+		"line 7: parse '9 2 1': expected 4 numbers, got 3":                     {"Before: [3, 2, 1, 1]", "9 2 1 2", "After:  [3, 2, 2, 1]", "", "", "", "9 2 1"},
+		"line 2, column 1: parse '20': expected an opcode number from 0 to 15": {"Before: [3, 2, 1, 1]", "20 2 1 2", "After:  [3, 2, 2, 1]"},
+		"line 5, column 1: parse '-1': expected an opcode number from 0 to 15": {"Before: [3, 2, 1, 1]", "9 2 1 2", "After:  [3, 2, 2, 1]", "", "-1 2 1 2"},
+	}
+
+	for expectedError, lines := range testCases {
+		_, _, err := ParseInput(lines)
+		assert.EqualError(t, err, expectedError)
+	}
+}
+
+func TestBuildCandidates(t *testing.T) {
+	samples, _, _ := ParseInput(testInput())
+	candidates := BuildCandidates(samples)
+
+	assert.Len(t, candidates, 16)
+	assert.Equal(t, []Opcode{Addi, Mulr, Seti}, candidates[9])
+	assert.Len(t, candidates[0], 16)
+}
+
+func TestSolveMapping(t *testing.T) {
+	candidates := identityCandidates()
+	candidates[0] = []Opcode{Addi, Mulr}
+	candidates[1] = []Opcode{Addr, Addi, Seti}
+
+	mapping, err := SolveMapping(candidates)
+
+	assert.NoError(t, err)
+	assert.Len(t, mapping, 16)
+	assert.Equal(t, Addi, mapping[0])
+	assert.Equal(t, Addr, mapping[1])
+	assert.Equal(t, Eqrr, mapping[15])
+
+	// The candidates are left untouched
+	assert.Len(t, candidates[1], 3)
+}
+
+func TestSolveMappingContradicting(t *testing.T) {
+	candidates := identityCandidates()
+	candidates[1] = []Opcode{Addr}
+
+	_, err := SolveMapping(candidates)
+
+	var mappingError *MappingError
+	assert.ErrorIs(t, err, ErrContradictingSamples)
+	assert.ErrorAs(t, err, &mappingError)
+	assert.Contains(t, err.Error(), " 1: -\n")
+}
+
+func TestSolveMappingAmbiguous(t *testing.T) {
+	candidates := identityCandidates()
+	candidates[0] = []Opcode{Addr, Addi}
+	candidates[1] = []Opcode{Addr, Addi}
+
+	_, err := SolveMapping(candidates)
+
+	var mappingError *MappingError
+	assert.ErrorIs(t, err, ErrAmbiguousMapping)
+	assert.ErrorAs(t, err, &mappingError)
+	assert.Equal(t, []Opcode{Addr, Addi}, mappingError.Candidates[1])
+	assert.Equal(t, []Opcode{Muli}, mappingError.Candidates[2])
+	assert.Contains(t, err.Error(), " 0: addr, addi\n 1: addr, addi\n 2: muli\n")
+}
+
+func TestDisassemble(t *testing.T) {
+	_, program, _ := ParseInput([]string{"9 5 0 1", "3 1 7 2", "0 2 1 0"})
+	mapping := MappingOpcode{9: Seti, 3: Muli, 0: Addr}
+
+	assert.Equal(t, []string{
+		"  0: seti 5 -> r1",
+		"  1: muli r1, 7 -> r2",
+		"  2: addr r2, r1 -> r0",
+	}, Disassemble(program, mapping))
+}
+
+func TestTraceProgram(t *testing.T) {
+	_, program, _ := ParseInput([]string{"9 5 0 1", "3 1 7 2", "0 2 1 0"})
+	mapping := MappingOpcode{9: Seti, 3: Muli, 0: Addr}
+
+	var trace strings.Builder
+	registers, err := TraceProgram(program, mapping, 4, &trace)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Registers{40, 5, 35, 0}, registers)
+	assert.Equal(t, []string{
+		"  0: seti 5 -> r1           [0, 0, 0, 0] -> [0, 5, 0, 0]",
+		"  1: muli r1, 7 -> r2       [0, 5, 0, 0] -> [0, 5, 35, 0]",
+		"  2: addr r2, r1 -> r0      [0, 5, 35, 0] -> [40, 5, 35, 0]",
+	}, strings.Split(strings.TrimSuffix(trace.String(), "\n"), "\n"))
+}
+
+func TestSolvePart2WithTrace(t *testing.T) {
+	var trace strings.Builder
+	input := exampleManual() + "\n\n\n9 5 0 1\n2 1 7 2\n0 2 1 0\n"
+
+	result := register.ExecDayWithInput(Day, input, register.Part2, register.WithTrace(&trace))
+
+	assert.NoError(t, result.Parts[0].Err)
+	assert.Equal(t, register.IntAnswer(40), result.Parts[0].Answer)
+
+	// First the disassembly of the program, then the trace ending with the answer in r0
+	assert.Equal(t, []string{
+		"  0: seti 5 -> r1",
+		"  1: muli r1, 7 -> r2",
+		"  2: addr r2, r1 -> r0",
+		"  0: seti 5 -> r1           [0, 0, 0, 0] -> [0, 5, 0, 0]",
+		"  1: muli r1, 7 -> r2       [0, 5, 0, 0] -> [0, 5, 35, 0]",
+		"  2: addr r2, r1 -> r0      [0, 5, 35, 0] -> [40, 5, 35, 0]",
+	}, strings.Split(strings.TrimSuffix(trace.String(), "\n"), "\n"))
+}
+
+func BenchmarkParseInput(b *testing.B) {
+	lines := utils.ReadFileAsLines("input.txt")
+
+	for i := 0; i < b.N; i++ {
+		ParseInput(lines)
+	}
+}
+
+func BenchmarkPart1(b *testing.B) {
+	samples, _, _ := ParseInput(utils.ReadFileAsLines("input.txt"))
+
+	for i := 0; i < b.N; i++ {
+		for _, sample := range samples {
+			sample.ValidOpcodes()
+		}
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	samples, program, _ := ParseInput(utils.ReadFileAsLines("input.txt"))
+
+	for i := 0; i < b.N; i++ {
+		mapping, _ := MapOpcodes(samples)
+		RunProgram(program, mapping, 4)
+	}
+}
+
+func BenchmarkRunProgram(b *testing.B) {
+	samples, program, _ := ParseInput(utils.ReadFileAsLines("input.txt"))
+	mapping, _ := MapOpcodes(samples)
+	translated := Translate(program, mapping)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		elfcode.NewMachine(translated, 4).Run()
+	}
+}
+
+// The program on the registers as they used to be: a map, reading 0 for unknown registers
+func BenchmarkRunProgramMapRegisters(b *testing.B) {
+	samples, program, _ := ParseInput(utils.ReadFileAsLines("input.txt"))
+	mapping, _ := MapOpcodes(samples)
+	translated := Translate(program, mapping)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		registers := map[int]int{0: 0, 1: 0, 2: 0, 3: 0}
+		for _, instruction := range translated.Instructions {
+			execOnMap(instruction, registers)
+		}
+	}
+}
+
+func execOnMap(i Instruction, registers map[int]int) {
+	var boolToInt = func(b bool) int {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	switch i.Opcode {
+	case Addr:
+		registers[i.Output] = registers[i.InputA] + registers[i.InputB]
+	case Addi:
+		registers[i.Output] = registers[i.InputA] + i.InputB
+	case Mulr:
+		registers[i.Output] = registers[i.InputA] * registers[i.InputB]
+	case Muli:
+		registers[i.Output] = registers[i.InputA] * i.InputB
+	case Banr:
+		registers[i.Output] = registers[i.InputA] & registers[i.InputB]
+	case Bani:
+		registers[i.Output] = registers[i.InputA] & i.InputB
+	case Borr:
+		registers[i.Output] = registers[i.InputA] | registers[i.InputB]
+	case Bori:
+		registers[i.Output] = registers[i.InputA] | i.InputB
+	case Setr:
+		registers[i.Output] = registers[i.InputA]
+	case Seti:
+		registers[i.Output] = i.InputA
+	case Gtir:
+		registers[i.Output] = boolToInt(i.InputA > registers[i.InputB])
+	case Gtri:
+		registers[i.Output] = boolToInt(registers[i.InputA] > i.InputB)
+	case Gtrr:
+		registers[i.Output] = boolToInt(registers[i.InputA] > registers[i.InputB])
+	case Eqir:
+		registers[i.Output] = boolToInt(i.InputA == registers[i.InputB])
+	case Eqri:
+		registers[i.Output] = boolToInt(registers[i.InputA] == i.InputB)
+	case Eqrr:
+		registers[i.Output] = boolToInt(registers[i.InputA] == registers[i.InputB])
+	}
+}
+
+// Every number has only its own opcode as candidate
+func identityCandidates() CandidateMatrix {
+	var candidates CandidateMatrix = make(CandidateMatrix)
+
+	for nr, opcode := range elfcode.AllOpcodes() {
+		candidates[nr] = []Opcode{opcode}
+	}
+
+	return candidates
+}
+
+// Samples that map every opcode number to the opcode with the same index in elfcode.AllOpcodes()
+func exampleManual() string {
+	var befores []elfcode.Registers = []elfcode.Registers{{3, 2, 1, 1}, {5, 7, 2, 0}, {1, 6, 0, 3}}
+	var arguments [][3]int = [][3]int{{2, 1, 2}, {1, 3, 0}, {0, 2, 3}, {3, 0, 1}}
+	var samples []string
+
+	for nr, opcode := range elfcode.AllOpcodes() {
+		for _, before := range befores {
+			for _, args := range arguments {
+				after := slices.Clone(before)
+				elfcode.Instruction{Opcode: opcode, InputA: args[0], InputB: args[1], Output: args[2]}.Exec(after)
+
+				samples = append(samples, fmt.Sprintf("Before: %s\n%d %d %d %d\nAfter:  %s\n",
+					before, nr, args[0], args[1], args[2], after))
+			}
+		}
+	}
+
+	return strings.Join(samples, "\n")
 }
 
 func testInput() []string {
@@ -0,0 +1,131 @@
+package day19gowiththeflow
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/elfcode"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
+)
+
+const (
+	Day string = "19"
+
+	NR_REGISTERS int = 6
+
+	// The number of instructions to run before the divisor loop must have been reached
+	MAX_SETUP_INSTRUCTIONS int = 1_000_000
+)
+
+var ErrNoDivisorLoop = errors.New("no divisor loop found")
+
+// The inner loop of the program, which adds every number that divides the target to register 0:
+//
+//	for a := 1; a <= target; a++ {
+//		for b := 1; b <= target; b++ {
+//			if a*b == target {
+//				registers[0] += a
+//			}
+//		}
+//	}
+type DivisorLoop struct {
+	Start          int // Index of the instruction that multiplies the two counters
+	TargetRegister int // The register holding the number to find the divisors of
+}
+
+// Run the program until it halts, and return register 0
+func RunProgram(program elfcode.Program, register0 int) (int, error) {
+	machine := elfcode.NewMachine(program, NR_REGISTERS)
+	machine.Registers[0] = register0
+
+	if err := machine.Run(); err != nil {
+		return 0, err
+	}
+
+	return machine.Registers[0], nil
+}
+
+// Find the divisor loop by its heart: a 'mulr', followed by an 'eqrr' comparing the product with the target
+func FindDivisorLoop(program elfcode.Program) (DivisorLoop, error) {
+	for idx := 0; idx+1 < len(program.Instructions); idx++ {
+		multiply, compare := program.Instructions[idx], program.Instructions[idx+1]
+		if multiply.Opcode != elfcode.Mulr || compare.Opcode != elfcode.Eqrr {
+			continue
+		}
+
+		switch multiply.Output {
+		case compare.InputA:
+			return DivisorLoop{Start: idx, TargetRegister: compare.InputB}, nil
+		case compare.InputB:
+			return DivisorLoop{Start: idx, TargetRegister: compare.InputA}, nil
+		}
+	}
+
+	return DivisorLoop{}, ErrNoDivisorLoop
+}
+
+// Run the program up to its divisor loop, which sets up the target, and then compute the outcome of the
+// loop, instead of executing it
+func RunProgramWithDivisorLoop(program elfcode.Program, register0 int) (int, error) {
+	loop, err := FindDivisorLoop(program)
+	if err != nil {
+		return 0, err
+	}
+
+	machine := elfcode.NewMachine(program, NR_REGISTERS)
+	machine.Registers[0] = register0
+
+	for machine.IP != loop.Start {
+		if machine.NrSteps >= MAX_SETUP_INSTRUCTIONS {
+			return 0, fmt.Errorf("%w: not reached after %d instructions", ErrNoDivisorLoop, MAX_SETUP_INSTRUCTIONS)
+		}
+		if ok, err := machine.Step(); !ok {
+			if err != nil {
+				return 0, err
+			}
+			return 0, fmt.Errorf("%w: program halted before reaching it", ErrNoDivisorLoop)
+		}
+	}
+
+	return machine.Registers[0] + SumOfDivisors(machine.Registers[loop.TargetRegister]), nil
+}
+
+func SumOfDivisors(number int) int {
+	var sum int = 0
+
+	for divisor := 1; divisor*divisor <= number; divisor++ {
+		if number%divisor == 0 {
+			sum += divisor
+			if other := number / divisor; other != divisor {
+				sum += other
+			}
+		}
+	}
+
+	return sum
+}
+
+type solver struct {
+	program elfcode.Program
+}
+
+func (s *solver) Parse(input string) (err error) {
+	s.program, err = elfcode.ParseProgram(utils.SplitLines(input))
+	return
+}
+
+func (s *solver) SolvePart1() (register.Answer, error) {
+	answer, err := RunProgram(s.program, 0)
+	return register.IntAnswer(answer), err
+}
+
+func (s *solver) SolvePart2() (register.Answer, error) {
+	answer, err := RunProgramWithDivisorLoop(s.program, 1)
+	return register.IntAnswer(answer), err
+}
+
+func init() {
+	// The input isn't part of the repository: add it to the inputs directory, or pass it with -input
+	register.Day(Day, nil, func() register.Solver { return &solver{} })
+}
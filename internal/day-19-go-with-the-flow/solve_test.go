@@ -0,0 +1,136 @@
+package day19gowiththeflow_test
+
+import (
+	"testing"
+
+	. "github.com/ewoutquax/advent-of-code-2018/internal/day-19-go-with-the-flow"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/elfcode"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunProgram(t *testing.T) {
+	program, _ := elfcode.ParseProgram(testInput())
+	register0, err := RunProgram(program, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 6, register0)
+}
+
+func TestFindDivisorLoop(t *testing.T) {
+	program, _ := elfcode.ParseProgram(testDivisorProgram())
+	loop, err := FindDivisorLoop(program)
+
+	assert.NoError(t, err)
+	assert.Equal(t, DivisorLoop{Start: 5, TargetRegister: 2}, loop)
+
+	program, _ = elfcode.ParseProgram(testInput())
+	_, err = FindDivisorLoop(program)
+
+	assert.ErrorIs(t, err, ErrNoDivisorLoop)
+}
+
+func TestRunProgramWithDivisorLoop(t *testing.T) {
+	program, _ := elfcode.ParseProgram(testDivisorProgram())
+
+	for _, register0 := range []int{0, 1} {
+		simulated, err := RunProgram(program, register0)
+		assert.NoError(t, err)
+
+		computed, err := RunProgramWithDivisorLoop(program, register0)
+		assert.NoError(t, err)
+
+		assert.Equal(t, simulated, computed)
+	}
+
+	computed, _ := RunProgramWithDivisorLoop(program, 0)
+	assert.Equal(t, 28, computed)
+	computed, _ = RunProgramWithDivisorLoop(program, 1)
+	assert.Equal(t, 72, computed)
+}
+
+func TestSumOfDivisors(t *testing.T) {
+	testCases := map[int]int{
+		1:  1,
+		12: 28,
+		16: 31,
+		17: 18,
+	}
+
+	for number, expected := range testCases {
+		assert.Equal(t, expected, SumOfDivisors(number))
+	}
+}
+
+func TestMissingInput(t *testing.T) {
+	register.SetInputDir("")
+	t.Setenv(register.InputDirEnv, "")
+
+	result := register.ExecDay(Day)
+
+	assert.ErrorIs(t, result.Err, register.ErrMissingInput)
+}
+
+func BenchmarkPart1(b *testing.B) {
+	lines, err := utils.ReadLines("input.txt")
+	if err != nil {
+		b.Skip("no input.txt")
+	}
+	program, _ := elfcode.ParseProgram(lines)
+
+	for i := 0; i < b.N; i++ {
+		RunProgram(program, 0)
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	lines, err := utils.ReadLines("input.txt")
+	if err != nil {
+		b.Skip("no input.txt")
+	}
+	program, _ := elfcode.ParseProgram(lines)
+
+	for i := 0; i < b.N; i++ {
+		RunProgramWithDivisorLoop(program, 1)
+	}
+}
+
+func testInput() []string {
+	return []string{
+		"#ip 0",
+		"seti 5 0 1",
+		"seti 6 0 2",
+		"addi 0 1 0",
+		"addr 1 2 3",
+		"setr 1 0 0",
+		"seti 8 0 4",
+		"seti 9 0 5",
+	}
+}
+
+// Sums the divisors of 12, or of 30 when register 0 starts at 1, the way the puzzle inputs do
+func testDivisorProgram() []string {
+	return []string{
+		"#ip 5",
+		"muli 0 18 2", // target = 18 * register 0
+		"addi 2 12 2", //   + 12
+		"seti 0 0 0",  // register 0 = 0
+		"seti 1 0 1",  // a = 1
+		"seti 1 0 4",  // b = 1
+		"mulr 1 4 3",  // a * b
+		"eqrr 3 2 3",  // == target?
+		"addr 3 5 5",
+		"addi 5 1 5",
+		"addr 1 0 0", // register 0 += a
+		"addi 4 1 4", // b++
+		"gtrr 4 2 3", // b > target?
+		"addr 5 3 5",
+		"seti 4 0 5", // next b
+		"addi 1 1 1", // a++
+		"gtrr 1 2 3", // a > target?
+		"addr 3 5 5",
+		"seti 3 0 5", // next a, starting at b = 1
+		"mulr 5 5 5", // halt
+	}
+}
@@ -1,6 +1,8 @@
 package day22modemaze
 
 import (
+	"embed"
+
 	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
 )
 
@@ -8,7 +10,9 @@ const (
 	Day string = "22"
 )
 
+//go:embed *.txt
+var files embed.FS
+
 func init() {
-	register.Day(Day+"a", solvePart1)
-	register.Day(Day+"b", solvePart2)
+	register.Day(Day, files, func() register.Solver { return &solver{} })
 }
@@ -1,12 +1,13 @@
 package day22modemaze
 
 import (
-	"container/heap"
+	"errors"
 	"fmt"
-	"math"
-	"strconv"
 	"strings"
 
+	"github.com/ewoutquax/advent-of-code-2018/pkg/grid"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/search"
 	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 )
 
@@ -24,12 +25,9 @@ func (e Equipment) toS() string {
 	}[e]
 }
 
-type Location struct {
-	X int
-	Y int
-}
+type Location = grid.Point
 
-func (l Location) toS() string {
+func locationToS(l Location) string {
 	return fmt.Sprintf("[%d, %d]", l.X, l.Y)
 }
 
@@ -43,42 +41,48 @@ const (
 	EquipmentNone
 )
 
-var (
-	cachedErosionLevels map[Location]int
-	visitedPaths        map[VisitedPathKey]int
-)
+// A cave holds the erosion levels computed so far, so every cave can be explored on its own
+type Cave struct {
+	Depth          CaveDepth
+	TargetLocation Location
+	erosionLevels  map[Location]int
+}
 
-func (l Location) GeologicalIndex(tl Location, cd CaveDepth) int {
+func NewCave(tl Location, cd CaveDepth) *Cave {
+	return &Cave{
+		Depth:          cd,
+		TargetLocation: tl,
+		erosionLevels:  make(map[Location]int, tl.X*tl.Y),
+	}
+}
+
+func (c *Cave) GeologicalIndex(l Location) int {
 	switch true {
 	case l.X == 0 && l.Y == 0:
 		return 0
-	case l.X == tl.X && l.Y == tl.Y:
+	case l.X == c.TargetLocation.X && l.Y == c.TargetLocation.Y:
 		return 0
 	case l.Y == 0:
 		return l.X * 16807
 	case l.X == 0:
 		return l.Y * 48271
 	default:
-		return Location{l.X - 1, l.Y}.ErosionLevel(tl, cd) * Location{l.X, l.Y - 1}.ErosionLevel(tl, cd)
+		return c.ErosionLevel(l.Add(grid.Left.Vector())) * c.ErosionLevel(l.Add(grid.Up.Vector()))
 	}
 }
 
-func (l Location) ErosionLevel(tl Location, cd CaveDepth) int {
-	if cachedErosionLevels == nil {
-		cachedErosionLevels = make(map[Location]int, tl.X*tl.Y)
-	}
-
-	if level, exists := cachedErosionLevels[l]; exists {
+func (c *Cave) ErosionLevel(l Location) int {
+	if level, exists := c.erosionLevels[l]; exists {
 		return level
 	}
 
-	newLevel := (l.GeologicalIndex(tl, cd) + int(cd)) % 20183
-	cachedErosionLevels[l] = newLevel
+	newLevel := (c.GeologicalIndex(l) + int(c.Depth)) % 20183
+	c.erosionLevels[l] = newLevel
 	return newLevel
 }
 
-func (l Location) ErosionLevelType(tl Location, cd CaveDepth) ErosionLevelType {
-	myType := l.ErosionLevel(tl, cd) % 3
+func (c *Cave) ErosionLevelType(l Location) ErosionLevelType {
+	myType := c.ErosionLevel(l) % 3
 
 	return ErosionLevelType(myType)
 }
@@ -88,54 +92,99 @@ type Input struct {
 	TargetLocation Location
 }
 
-func FastestTime(tl Location, cd CaveDepth) int {
-	InitVisitedPaths()
-	var minNrMinutes int = math.MaxInt
-
-	var initPath = Path{
-		Location:         Location{0, 0},
-		CurrentEquipment: EquipmentTorch,
-		NrMinutes:        0,
+func FastestTime(tl Location, cd CaveDepth) (int, error) {
+	route, err := FastestRoute(tl, cd)
+	if err != nil {
+		return 0, err
 	}
 
-	pathHeap := PathHeap{initPath}
-
-	visitedPaths[initPath.VisitedKey()] = 0
-	heap.Init(&pathHeap)
+	return route[len(route)-1].NrMinutes, nil
+}
 
-	for pathHeap.Len() > 0 {
-		currentPath := heap.Pop(&pathHeap).(Path)
+// The quickest route from the mouth of the cave to the target, arriving with the torch in hand
+func FastestRoute(tl Location, cd CaveDepth) ([]Path, error) {
+	if tl.X < 0 || tl.Y < 0 {
+		// The search would explore the infinite cave forever
+		return nil, fmt.Errorf("target %s is outside the cave", locationToS(tl))
+	}
 
-		if currentPath.Location.X == tl.X && currentPath.Location.Y == tl.Y && currentPath.CurrentEquipment == EquipmentTorch {
-			minNrMinutes = currentPath.NrMinutes
-			fmt.Printf("Found path to end with torch: %v\n", minNrMinutes)
-		}
+	var cave *Cave = NewCave(tl, cd)
+	var target = pathState{Location: tl, CurrentEquipment: EquipmentTorch}
 
-		nextPaths := currentPath.getNextPaths(tl, cd)
-		for _, nextPath := range nextPaths {
-			if nextPath.NrMinutes < minNrMinutes && !(nextPath.IsVisited()) {
-				nextPath.SetVisited()
+	solution, err := search.AStar(
+		pathState{Location: grid.Pt(0, 0), CurrentEquipment: EquipmentTorch},
+		func(s pathState) bool { return s == target },
+		func(s pathState) []search.Edge[pathState] { return s.getNextStates(cave) },
+		func(s pathState) int { return s.Location.ManhattanDistance(tl) },
+	)
+	if err != nil {
+		return nil, err
+	}
 
-				heap.Push(&pathHeap, nextPath)
+	var route []Path = make([]Path, 0, len(solution.Path))
+	var nrMinutes int = 0
+	for idx, state := range solution.Path {
+		if idx > 0 {
+			if state.CurrentEquipment != solution.Path[idx-1].CurrentEquipment {
+				nrMinutes += 7
+			} else {
+				nrMinutes += 1
 			}
 		}
+		route = append(route, Path{Location: state.Location, CurrentEquipment: state.CurrentEquipment, NrMinutes: nrMinutes})
 	}
 
-	return minNrMinutes
+	return route, nil
 }
 
-func ParseInput(lines []string) Input {
-	partsDepth := strings.Split(lines[0], ": ")
-	partsLocation := strings.Split(lines[1], ": ")
-	partsLocations := strings.Split(partsLocation[1], ",")
+func ParseInput(lines []string) (Input, error) {
+	if len(lines) < 2 {
+		return Input{}, fmt.Errorf("expected 2 lines with depth and target, got %d", len(lines))
+	}
+
+	depth, found := strings.CutPrefix(lines[0], "depth: ")
+	if !found {
+		return Input{}, &utils.ParseError{Line: 1, Input: lines[0], Err: errors.New("expected 'depth: D'")}
+	}
+	target, found := strings.CutPrefix(lines[1], "target: ")
+	if !found {
+		return Input{}, &utils.ParseError{Line: 2, Input: lines[1], Err: errors.New("expected 'target: X,Y'")}
+	}
+	x, y, found := strings.Cut(target, ",")
+	if !found {
+		return Input{}, &utils.ParseError{Line: 2, Input: lines[1], Err: errors.New("expected 'target: X,Y'")}
+	}
+
+	nrDepth, err := parseNonNegative(depth, 1, len("depth: ")+1)
+	if err != nil {
+		return Input{}, err
+	}
+	nrX, err := parseNonNegative(x, 2, len("target: ")+1)
+	if err != nil {
+		return Input{}, err
+	}
+	nrY, err := parseNonNegative(y, 2, len("target: ")+len(x)+2)
+	if err != nil {
+		return Input{}, err
+	}
 
 	return Input{
-		CaveDepth: CaveDepth(convAtoI(partsDepth[1])),
-		TargetLocation: Location{
-			X: convAtoI(partsLocations[0]),
-			Y: convAtoI(partsLocations[1]),
-		},
+		CaveDepth:      CaveDepth(nrDepth),
+		TargetLocation: Location{X: nrX, Y: nrY},
+	}, nil
+}
+
+// The depth and the target coordinates can't be negative: the cave starts at 0,0 and extends down and to the right
+func parseNonNegative(s string, line, column int) (int, error) {
+	value, err := utils.ParseIntAt(s, line, column)
+	if err != nil {
+		return 0, err
+	}
+	if value < 0 {
+		return 0, &utils.ParseError{Line: line, Column: column, Input: s, Err: errors.New("expected a number of at least 0")}
 	}
+
+	return value, nil
 }
 
 func allowedEquipments(t ErosionLevelType) []Equipment {
@@ -148,21 +197,14 @@ func allowedEquipments(t ErosionLevelType) []Equipment {
 	}[t]
 }
 
-func convAtoI(s string) int {
-	nr, err := strconv.Atoi(s)
-	if err != nil {
-		panic(err)
-	}
-	return nr
-}
-
 func CalculateRisk(tl Location, cd CaveDepth) int {
+	var cave *Cave = NewCave(tl, cd)
 	var sum int = 0
 
 	for y := 0; y <= tl.Y; y++ {
 		for x := 0; x <= tl.X; x++ {
-			location := Location{x, y}
-			sum += int(location.ErosionLevelType(tl, cd))
+			location := grid.Pt(x, y)
+			sum += int(cave.ErosionLevelType(location))
 		}
 	}
 
@@ -177,26 +219,27 @@ func allTypes() []ErosionLevelType {
 	}
 }
 
-func solvePart1(inputFile string) {
-	lines := utils.ReadFileAsLines(inputFile)
-
-	input := ParseInput(lines)
+type solver struct {
+	input Input
+}
 
-	fmt.Printf("Result of day-%s / part-1: %d\n", Day, CalculateRisk(
-		input.TargetLocation,
-		input.CaveDepth,
-	))
+func (s *solver) Parse(input string) (err error) {
+	s.input, err = ParseInput(utils.SplitLines(input))
+	return
 }
 
-func solvePart2(inputFile string) {
-	lines := utils.ReadFileAsLines(inputFile)
-	input := ParseInput(lines)
+func (s *solver) SolvePart1() (register.Answer, error) {
+	return register.IntAnswer(CalculateRisk(
+		s.input.TargetLocation,
+		s.input.CaveDepth,
+	)), nil
+}
 
-	fmt.Printf("Result of day-%s / part-2: %d\n", Day, FastestTime(input.TargetLocation, input.CaveDepth))
+func (s *solver) SolvePart2() (register.Answer, error) {
+	nrMinutes, err := FastestTime(s.input.TargetLocation, s.input.CaveDepth)
+	if err != nil {
+		return nil, err
+	}
 
-	/**
-	  Answers
-	  -------
-	  1027: Too low
-	*/
+	return register.IntAnswer(nrMinutes), nil
 }
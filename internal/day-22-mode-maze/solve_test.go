@@ -5,72 +5,73 @@ import (
 	"testing"
 
 	. "github.com/ewoutquax/advent-of-code-2018/internal/day-22-mode-maze"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGeologicalIndex(t *testing.T) {
-	targetLocation := Location{10, 10}
+	targetLocation := Location{X: 10, Y: 10}
 	caveDepth := CaveDepth(510)
 
 	testCases := map[Location]int{
-		{0, 0}: 0,
-		{1, 0}: 16807,
-		{0, 1}: 48271,
-		{1, 1}: 145722555,
+		{X: 0, Y: 0}: 0,
+		{X: 1, Y: 0}: 16807,
+		{X: 0, Y: 1}: 48271,
+		{X: 1, Y: 1}: 145722555,
 	}
 
 	for inputLocation, expectedResult := range testCases {
-		actualResult := inputLocation.GeologicalIndex(targetLocation, caveDepth)
+		actualResult := NewCave(targetLocation, caveDepth).GeologicalIndex(inputLocation)
 		assert.Equal(t, expectedResult, actualResult)
 	}
 }
 
 func TestErosionLevel(t *testing.T) {
-	targetLocation := Location{10, 10}
+	targetLocation := Location{X: 10, Y: 10}
 	caveDepth := CaveDepth(510)
 
 	testCases := map[Location]int{
-		{0, 0}: 510,
-		{1, 0}: 17317,
-		{0, 1}: 8415,
-		{1, 1}: 1805,
+		{X: 0, Y: 0}: 510,
+		{X: 1, Y: 0}: 17317,
+		{X: 0, Y: 1}: 8415,
+		{X: 1, Y: 1}: 1805,
 	}
 
 	for inputLocation, expectedResult := range testCases {
-		actualResult := inputLocation.ErosionLevel(targetLocation, caveDepth)
+		actualResult := NewCave(targetLocation, caveDepth).ErosionLevel(inputLocation)
 		assert.Equal(t, expectedResult, actualResult)
 	}
 }
 
 func TestErosionLevelType(t *testing.T) {
-	targetLocation := Location{10, 10}
+	targetLocation := Location{X: 10, Y: 10}
 	caveDepth := CaveDepth(510)
 
 	testCases := map[Location]ErosionLevelType{
-		{0, 0}: ErosionLevelTypeRocky,
-		{1, 0}: ErosionLevelTypeWet,
-		{0, 1}: ErosionLevelTypeRocky,
-		{1, 1}: ErosionLevelTypeNarrow,
+		{X: 0, Y: 0}: ErosionLevelTypeRocky,
+		{X: 1, Y: 0}: ErosionLevelTypeWet,
+		{X: 0, Y: 1}: ErosionLevelTypeRocky,
+		{X: 1, Y: 1}: ErosionLevelTypeNarrow,
 	}
 
 	for inputLocation, expectedResult := range testCases {
-		actualResult := inputLocation.ErosionLevelType(targetLocation, caveDepth)
+		actualResult := NewCave(targetLocation, caveDepth).ErosionLevelType(inputLocation)
 		assert.Equal(t, expectedResult, actualResult)
 	}
 }
 
 func TestCalculateRisk(t *testing.T) {
-	targetLocation := Location{10, 10}
+	targetLocation := Location{X: 10, Y: 10}
 	caveDepth := CaveDepth(510)
 
 	assert.Equal(t, 114, CalculateRisk(targetLocation, caveDepth))
 }
 
 func TestDrawCave(t *testing.T) {
-	targetLocation := Location{10, 10}
+	targetLocation := Location{X: 10, Y: 10}
 	caveDepth := CaveDepth(510)
 
-	// cachedErosionLevels = make(map[Location]int, targetLocation.X*targetLocation.Y)
+	cave := NewCave(targetLocation, caveDepth)
 
 	for y := 0; y < 16; y++ {
 		for x := 0; x < 16; x++ {
@@ -78,7 +79,7 @@ func TestDrawCave(t *testing.T) {
 				X: x,
 				Y: y,
 			}
-			locationType := location.ErosionLevelType(targetLocation, caveDepth)
+			locationType := cave.ErosionLevelType(location)
 
 			stringType := map[ErosionLevelType]string{
 				ErosionLevelTypeRocky:  ".",
@@ -95,54 +96,85 @@ func TestDrawCave(t *testing.T) {
 }
 
 func TestParseInput(t *testing.T) {
-	input := ParseInput(testInput())
+	input, err := ParseInput(testInput())
 
+	assert.NoError(t, err)
 	assert.IsType(t, Input{}, input)
 	assert.Equal(t, CaveDepth(4002), input.CaveDepth)
 	assert.Equal(t, 5, input.TargetLocation.X)
 	assert.Equal(t, 746, input.TargetLocation.Y)
 }
 
-func TestPathVisitedKey(t *testing.T) {
-	path := Path{
-		Location:         Location{42, 1337},
-		CurrentEquipment: EquipmentTorch,
-		NrMinutes:        0,
+func TestParseInputInvalid(t *testing.T) {
+	testCases := map[string][]string{
+		"line 1: parse 'depht: 4002': expected 'depth: D'":               {"depht: 4002", "target: 5,746"},
+		"line 2, column 11: parse '74x': invalid syntax":                 {"depth: 4002", "target: 5,74x"},
+		"expected 2 lines with depth and target, got 1":                  {"depth: 4002"},
+		"line 2: parse 'target: 5;746': expected 'target: X,Y'":          {"depth: 4002", "target: 5;746"},
+		"line 1, column 8: parse '4002 feet': invalid syntax":            {"depth: 4002 feet", "target: 5,746"},
+		"line 1, column 8: parse '-1': expected a number of at least 0":  {"depth: -1", "target: 5,746"},
+		"line 2, column 9: parse '-10': expected a number of at least 0": {"depth: 510", "target: -10,10"},
+		"line 2, column 12: parse '-5': expected a number of at least 0": {"depth: 510", "target: 10,-5"},
 	}
 
-	assert.Equal(t, VisitedPathKey("[42, 1337]/5"), path.VisitedKey())
+	for expectedError, lines := range testCases {
+		_, err := ParseInput(lines)
+		assert.EqualError(t, err, expectedError)
+	}
 }
 
-func TestPreventDuplicateVisitedPaths(t *testing.T) {
-	InitVisitedPaths()
+func TestFastestTime(t *testing.T) {
+	targetLocation := Location{X: 10, Y: 10}
+	caveDepth := CaveDepth(510)
 
-	path := Path{
-		Location:         Location{1337, 42},
-		CurrentEquipment: EquipmentTorch,
-		NrMinutes:        18,
-	}
+	nrMinutes, err := FastestTime(targetLocation, caveDepth)
+	assert.NoError(t, err)
+	assert.Equal(t, 45, nrMinutes)
 
-	assert.False(t, path.IsVisited())
+	_, err = FastestTime(Location{X: -10, Y: 10}, caveDepth)
+	assert.EqualError(t, err, "target [-10, 10] is outside the cave")
+}
+
+func TestFastestRoute(t *testing.T) {
+	targetLocation := Location{X: 10, Y: 10}
+	caveDepth := CaveDepth(510)
 
-	path.SetVisited()
-	assert.True(t, path.IsVisited())
+	route, err := FastestRoute(targetLocation, caveDepth)
+	assert.NoError(t, err)
+	expected := solutionExample2()
+
+	// Other routes take just as long; the fastest route only has to start and end the same
+	assert.Equal(t, expected[0], route[0].ToS())
+	assert.Equal(t, expected[len(expected)-1], route[len(route)-1].ToS())
+
+	// Every step either moves to a neighbour, or switches equipment
+	for idx := 1; idx < len(route); idx++ {
+		previous, current := route[idx-1], route[idx]
+		if current.Location == previous.Location {
+			assert.NotEqual(t, previous.CurrentEquipment, current.CurrentEquipment)
+			assert.Equal(t, previous.NrMinutes+7, current.NrMinutes)
+		} else {
+			assert.Equal(t, 1, previous.Location.ManhattanDistance(current.Location))
+			assert.Equal(t, previous.CurrentEquipment, current.CurrentEquipment)
+			assert.Equal(t, previous.NrMinutes+1, current.NrMinutes)
+		}
+	}
+}
 
-	// Changing gear with see this as a new entry
-	path.CurrentEquipment = EquipmentClimbingGear
-	assert.False(t, path.IsVisited())
+func BenchmarkPart1(b *testing.B) {
+	input, _ := ParseInput(utils.ReadFileAsLines("input.txt"))
 
-	// Lowering the NrMinutes should see the new path as a better alternative
-	path.SetVisited()
-	assert.True(t, path.IsVisited())
-	path.NrMinutes -= 1
-	assert.False(t, path.IsVisited())
+	for i := 0; i < b.N; i++ {
+		CalculateRisk(input.TargetLocation, input.CaveDepth)
+	}
 }
 
-func TestFastestTime(t *testing.T) {
-	targetLocation := Location{10, 10}
-	caveDepth := CaveDepth(510)
+func BenchmarkPart2(b *testing.B) {
+	input, _ := ParseInput(utils.ReadFileAsLines("input.txt"))
 
-	assert.Equal(t, 45, FastestTime(targetLocation, caveDepth))
+	for i := 0; i < b.N; i++ {
+		FastestTime(input.TargetLocation, input.CaveDepth)
+	}
 }
 
 func testInput() []string {
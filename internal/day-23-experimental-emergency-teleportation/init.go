@@ -1,12 +1,18 @@
 package day23experimentalemergencyteleportation
 
-import "github.com/ewoutquax/advent-of-code-2018/pkg/register"
+import (
+	"embed"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+)
 
 const (
 	Day string = "23"
 )
 
+//go:embed *.txt
+var files embed.FS
+
 func init() {
-	register.Day(Day+"a", solvePart1)
-	register.Day(Day+"b", solvePart2)
+	register.Day(Day, files, func() register.Solver { return &solver{} })
 }
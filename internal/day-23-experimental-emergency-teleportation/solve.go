@@ -2,10 +2,9 @@ package day23experimentalemergencyteleportation
 
 import (
 	"errors"
-	"fmt"
-	"strconv"
-	"strings"
+	"regexp"
 
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
 	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 )
 
@@ -28,10 +27,6 @@ func (b Bot) Distance(target Bot) int {
 		abs(target.Z-b.Z)
 }
 
-func (b Bot) toS() string {
-	return fmt.Sprintf("Pos=<%d,%d,%d>, r=%d", b.X, b.Y, b.Z, b.Radius)
-}
-
 func FindLocationWithMostCoverage(bots []Bot) Location {
 	var bestBot Bot
 	var maxCount int = 0
@@ -40,38 +35,26 @@ func FindLocationWithMostCoverage(bots []Bot) Location {
 
 	for _, sourceBot := range bots {
 		count := 1
-		fmt.Println("\nNew sourcebot")
 		newBot := sourceBot
 
 		for _, targetBot := range bots {
 			tempBot, err := GenerateBotWithFullOverlap(newBot, targetBot)
 			if err == nil {
-				// fmt.Printf("newBot: '%s' and targetBot '%s' have full overlap at '%s'\n", newBot.toS(), targetBot.toS(), tempBot.toS())
-
 				count++
 				newBot = tempBot
-				// } else {
-				// fmt.Printf("newBot: '%s' and targetBot '%s' have NO overlap\n", newBot.toS(), targetBot.toS())
 			}
 
 		}
 
 		if maxCount < count {
-			fmt.Printf("new bestLocation with bot: %v\n", newBot.toS())
 			bestBot = newBot
 			maxCount = count
 			bestBots = append(bestBots, newBot)
 		}
 	}
 
-	fmt.Println("FindLocationWithMostCoverage: bestBots (after round 1)")
-	fmt.Println("------------------------------------------------------")
-	fmt.Printf("len(bestBots): %v\n", len(bestBots))
-
-	var bestestBots []Bot = make([]Bot, 0, len(bestBots))
 	for _, sourceBot := range bestBots {
 		count := 1
-		fmt.Println("\nNew sourceBestBot")
 		newBot := sourceBot
 
 		for _, targetBot := range bots {
@@ -86,18 +69,9 @@ func FindLocationWithMostCoverage(bots []Bot) Location {
 		if maxCount < count {
 			bestBot = newBot
 			maxCount = count
-			bestestBots = append(bestestBots, newBot)
 		}
 	}
 
-	fmt.Println("FindLocationWithMostCoverage: bestestBots (after round 2)")
-	fmt.Println("---------------------------------------------------------")
-	for idx, b := range bestestBots {
-		fmt.Printf("%d: %s\n", idx, b.toS())
-	}
-
-	fmt.Printf("overall bestBot: %s\n", bestBot.toS())
-
 	return bestBot.Location
 }
 
@@ -178,44 +152,52 @@ func minimum(ints ...int) int {
 	return i2
 }
 
-func ParseInput(lines []string) []Bot {
+func ParseInput(lines []string) ([]Bot, error) {
 	var bots []Bot = make([]Bot, 0, len(lines))
 
-	for _, line := range lines {
-		bots = append(bots, parseLine(line))
+	for idx, line := range lines {
+		bot, err := parseLine(line, idx+1)
+		if err != nil {
+			return nil, err
+		}
+		bots = append(bots, bot)
 	}
 
-	return bots
+	return bots, nil
 }
 
-func parseLine(line string) Bot {
-	parts := strings.Split(line, ", ")
+var botPattern = regexp.MustCompile(`(?i)^pos=<([^,>]*),([^,>]*),([^,>]*)>, r=(.*)$`)
+
+func parseLine(line string, lineNr int) (Bot, error) {
+	match := botPattern.FindStringSubmatchIndex(line)
+	if match == nil {
+		return Bot{}, &utils.ParseError{
+			Line:  lineNr,
+			Input: line,
+			Err:   errors.New("expected 'pos=<X,Y,Z>, r=R'"),
+		}
+	}
 
-	locationParts := strings.TrimRight(
-		strings.Split(parts[0], "<")[1],
-		">",
-	)
+	// Parse the 4 submatches, with their column in the line
+	var values [4]int
+	for idx := range values {
+		start, end := match[2+idx*2], match[3+idx*2]
 
-	locations := strings.Split(locationParts, ",")
-	radius := strings.Split(parts[1], "=")[1]
+		value, err := utils.ParseIntAt(line[start:end], lineNr, start+1)
+		if err != nil {
+			return Bot{}, err
+		}
+		values[idx] = value
+	}
 
 	return Bot{
 		Location: Location{
-			X: convAtoi(locations[0]),
-			Y: convAtoi(locations[1]),
-			Z: convAtoi(locations[2]),
+			X: values[0],
+			Y: values[1],
+			Z: values[2],
 		},
-		Radius: Radius(convAtoi(radius)),
-	}
-}
-
-func convAtoi(s string) int {
-	nr, err := strconv.Atoi(s)
-	if err != nil {
-		panic(err)
-	}
-
-	return nr
+		Radius: Radius(values[3]),
+	}, nil
 }
 
 func abs(i int) int {
@@ -226,27 +208,26 @@ func abs(i int) int {
 	return i
 }
 
-func solvePart1(inputFile string) {
-	lines := utils.ReadFileAsLines(inputFile)
-	bots := ParseInput(lines)
+type solver struct {
+	bots []Bot
+}
+
+func (s *solver) Parse(input string) error {
+	bots, err := ParseInput(utils.SplitLines(input))
+	s.bots = bots
 
-	fmt.Printf("Result of day-%s / part-1: %d\n", Day, CountWithinRangeOfStrongest(bots))
+	return err
 }
 
-func solvePart2(inputFile string) {
-	lines := utils.ReadFileAsLines(inputFile)
-	bots := ParseInput(lines)
+func (s *solver) SolvePart1() (register.Answer, error) {
+	return register.IntAnswer(CountWithinRangeOfStrongest(s.bots)), nil
+}
 
-	location := FindLocationWithMostCoverage(bots)
+func (s *solver) SolvePart2() (register.Answer, error) {
+	location := FindLocationWithMostCoverage(s.bots)
 	distance := abs(location.X) +
 		abs(location.Y) +
 		abs(location.Z)
 
-		/**
-		  Answers:
-		  --------
-		  120339115: too high
-		*/
-
-	fmt.Printf("Result of day-%s / part-2: %d\n", Day, distance)
+	return register.IntAnswer(distance), nil
 }
@@ -1,17 +1,20 @@
 package day23experimentalemergencyteleportation_test
 
 import (
+	"strings"
 	"testing"
 
 	. "github.com/ewoutquax/advent-of-code-2018/internal/day-23-experimental-emergency-teleportation"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestParseInput(t *testing.T) {
 	assert := assert.New(t)
 
-	bots := ParseInput(testInput())
+	bots, err := ParseInput(testInput())
 
+	assert.NoError(err)
 	assert.IsType(Bot{}, bots[0])
 	assert.Len(bots, 9)
 
@@ -31,8 +34,21 @@ func TestParseInput(t *testing.T) {
 	assert.Equal(Radius(1), lastBot.Radius)
 }
 
+func TestParseInputInvalid(t *testing.T) {
+	testCases := map[string]string{
+		"pos=<0,0,0>, r=4\npos=<1,x,0>, r=1": "line 2, column 8: parse 'x': invalid syntax",
+		"pos=<0,0,0>, r=4\npos=<1,0,0>, r=":  "line 2, column 16: parse '': invalid syntax",
+		"pos=<0,0>, r=4":                     "line 1: parse 'pos=<0,0>, r=4': expected 'pos=<X,Y,Z>, r=R'",
+	}
+
+	for input, expectedError := range testCases {
+		_, err := ParseInput(strings.Split(input, "\n"))
+		assert.EqualError(t, err, expectedError)
+	}
+}
+
 func TestDistanceBetweenBots(t *testing.T) {
-	bots := ParseInput(testInput())
+	bots, _ := ParseInput(testInput())
 
 	testCases := map[Bot]int{
 		bots[0]: 0,
@@ -53,7 +69,7 @@ func TestDistanceBetweenBots(t *testing.T) {
 }
 
 func TestCountWithinRangeOfStrongest(t *testing.T) {
-	bots := ParseInput(testInput())
+	bots, _ := ParseInput(testInput())
 
 	assert.Equal(t, 7, CountWithinRangeOfStrongest(bots))
 }
@@ -71,7 +87,7 @@ func TestCalculateBotWithFullOverlap(t *testing.T) {
 	var overlapBot Bot
 	var err error
 
-	bots := ParseInput(lines)
+	bots, _ := ParseInput(lines)
 
 	// No overlap exists
 	overlapBot, err = GenerateBotWithFullOverlap(bots[2], bots[3])
@@ -108,7 +124,7 @@ func TestCalculateBotWithFullOverlap(t *testing.T) {
 }
 
 func TestFindLocationWithMostCoverage(t *testing.T) {
-	bots := ParseInput(testInput2())
+	bots, _ := ParseInput(testInput2())
 
 	loc := FindLocationWithMostCoverage(bots)
 	assert.IsType(t, Location{}, loc)
@@ -117,6 +133,30 @@ func TestFindLocationWithMostCoverage(t *testing.T) {
 	assert.Equal(t, 12, loc.Z)
 }
 
+func BenchmarkParseInput(b *testing.B) {
+	lines := utils.ReadFileAsLines("input.txt")
+
+	for i := 0; i < b.N; i++ {
+		ParseInput(lines)
+	}
+}
+
+func BenchmarkPart1(b *testing.B) {
+	bots, _ := ParseInput(utils.ReadFileAsLines("input.txt"))
+
+	for i := 0; i < b.N; i++ {
+		CountWithinRangeOfStrongest(bots)
+	}
+}
+
+func BenchmarkPart2(b *testing.B) {
+	bots, _ := ParseInput(utils.ReadFileAsLines("input.txt"))
+
+	for i := 0; i < b.N; i++ {
+		FindLocationWithMostCoverage(bots)
+	}
+}
+
 func testInput() []string {
 	return []string{
 		"Pos=<0,0,0>, r=4",
@@ -0,0 +1,7 @@
+// Package days wires up every puzzle in internal/.
+//
+// Importing it (for its side effects) registers all days with pkg/register.
+// The imports live in days_gen.go, which is kept in sync with the directory tree by go generate.
+package days
+
+//go:generate go run ../../cmd/gendays -dir .. -out days_gen.go -pkg days
@@ -0,0 +1,14 @@
+// Code generated by cmd/gendays; DO NOT EDIT.
+
+package days
+
+import (
+	_ "github.com/ewoutquax/advent-of-code-2018/internal/day-05-alchemical-reduction"
+	_ "github.com/ewoutquax/advent-of-code-2018/internal/day-06-chronal-coordinates"
+	_ "github.com/ewoutquax/advent-of-code-2018/internal/day-07-the-sum-of-its-parts"
+	_ "github.com/ewoutquax/advent-of-code-2018/internal/day-13-mine-cart-madness"
+	_ "github.com/ewoutquax/advent-of-code-2018/internal/day-16-chronical-classification"
+	_ "github.com/ewoutquax/advent-of-code-2018/internal/day-19-go-with-the-flow"
+	_ "github.com/ewoutquax/advent-of-code-2018/internal/day-22-mode-maze"
+	_ "github.com/ewoutquax/advent-of-code-2018/internal/day-23-experimental-emergency-teleportation"
+)
@@ -0,0 +1,69 @@
+package days_test
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	_ "github.com/ewoutquax/advent-of-code-2018/internal/days"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+var dayDirPattern = regexp.MustCompile(`^day-(\d{2})-[a-z0-9-]+$`)
+
+// When this test fails, run `go generate ./internal/days`
+func TestAllDayDirsAreImported(t *testing.T) {
+	assert.Equal(t, dayDirs(t), importedDayDirs(t))
+}
+
+func TestAllDayDirsAreRegistered(t *testing.T) {
+	registeredDays := register.GetAllDays()
+
+	for _, dir := range dayDirs(t) {
+		nrDay := dayDirPattern.FindStringSubmatch(dir)[1]
+		assert.Contains(t, registeredDays, nrDay, dir)
+	}
+}
+
+func dayDirs(t *testing.T) []string {
+	entries, err := os.ReadDir("..")
+	assert.NoError(t, err)
+
+	var dirs []string = make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || !dayDirPattern.MatchString(entry.Name()) {
+			continue
+		}
+
+		goFiles, err := os.ReadDir("../" + entry.Name())
+		assert.NoError(t, err)
+		for _, goFile := range goFiles {
+			if strings.HasSuffix(goFile.Name(), ".go") {
+				dirs = append(dirs, entry.Name())
+				break
+			}
+		}
+	}
+
+	return dirs
+}
+
+func importedDayDirs(t *testing.T) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "days_gen.go", nil, parser.ImportsOnly)
+	assert.NoError(t, err)
+
+	var dirs []string = make([]string, 0, len(file.Imports))
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		assert.NoError(t, err)
+		dirs = append(dirs, path.Base(importPath))
+	}
+
+	return dirs
+}
@@ -0,0 +1,123 @@
+// Package dag holds a directed graph of named nodes, with topological sorting, cycle detection,
+// transitive reduction and reachability queries
+package dag
+
+// A directed graph; an edge from a to b means a comes before b.
+// Nodes and edges are kept in the order they were added
+type Graph struct {
+	names        []string
+	index        map[string]int
+	successors   [][]int
+	predecessors [][]int
+	edges        map[[2]int]bool
+	edgeOrder    [][2]int
+}
+
+type Edge struct {
+	From string
+	To   string
+}
+
+func New() *Graph {
+	return &Graph{
+		index: make(map[string]int),
+		edges: make(map[[2]int]bool),
+	}
+}
+
+// Add the node, unless it already exists
+func (g *Graph) AddNode(name string) {
+	g.id(name)
+}
+
+// Add an edge, and the nodes when they don't exist yet. Duplicate edges are ignored
+func (g *Graph) AddEdge(from, to string) {
+	var edge [2]int = [2]int{g.id(from), g.id(to)}
+	if g.edges[edge] {
+		return
+	}
+
+	g.edges[edge] = true
+	g.edgeOrder = append(g.edgeOrder, edge)
+	g.successors[edge[0]] = append(g.successors[edge[0]], edge[1])
+	g.predecessors[edge[1]] = append(g.predecessors[edge[1]], edge[0])
+}
+
+func (g *Graph) Has(name string) bool {
+	_, exists := g.index[name]
+	return exists
+}
+
+func (g *Graph) HasEdge(from, to string) bool {
+	fromId, fromExists := g.index[from]
+	toId, toExists := g.index[to]
+
+	return fromExists && toExists && g.edges[[2]int{fromId, toId}]
+}
+
+func (g *Graph) Len() int {
+	return len(g.names)
+}
+
+func (g *Graph) Nodes() []string {
+	return append([]string(nil), g.names...)
+}
+
+func (g *Graph) Edges() []Edge {
+	var edges []Edge = make([]Edge, 0, len(g.edgeOrder))
+	for _, edge := range g.edgeOrder {
+		edges = append(edges, Edge{From: g.names[edge[0]], To: g.names[edge[1]]})
+	}
+
+	return edges
+}
+
+// The nodes with an edge to this node; nil for unknown nodes
+func (g *Graph) Predecessors(name string) []string {
+	if id, exists := g.index[name]; exists {
+		return g.namesOf(g.predecessors[id])
+	}
+	return nil
+}
+
+// The nodes this node has an edge to; nil for unknown nodes
+func (g *Graph) Successors(name string) []string {
+	if id, exists := g.index[name]; exists {
+		return g.namesOf(g.successors[id])
+	}
+	return nil
+}
+
+// The nodes without predecessors
+func (g *Graph) Roots() []string {
+	var roots []string
+	for id, name := range g.names {
+		if len(g.predecessors[id]) == 0 {
+			roots = append(roots, name)
+		}
+	}
+
+	return roots
+}
+
+func (g *Graph) id(name string) int {
+	if id, exists := g.index[name]; exists {
+		return id
+	}
+
+	g.index[name] = len(g.names)
+	g.names = append(g.names, name)
+	g.successors = append(g.successors, nil)
+	g.predecessors = append(g.predecessors, nil)
+
+	return len(g.names) - 1
+}
+
+func (g *Graph) namesOf(ids []int) []string {
+	var names []string = make([]string, 0, len(ids))
+	for _, id := range ids {
+		names = append(names, g.names[id])
+	}
+
+	return names
+}
@@ -0,0 +1,44 @@
+package dag_test
+
+import (
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/dag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraph(t *testing.T) {
+	assert := assert.New(t)
+
+	g := exampleGraph()
+	g.AddEdge("C", "A")
+	g.AddNode("lonely node")
+
+	assert.Equal(7, g.Len())
+	assert.Equal([]string{"C", "A", "F", "B", "D", "E", "lonely node"}, g.Nodes())
+	assert.Len(g.Edges(), 7)
+	assert.Equal(dag.Edge{From: "C", To: "A"}, g.Edges()[0])
+	assert.True(g.Has("F"))
+	assert.False(g.Has("G"))
+	assert.True(g.HasEdge("A", "B"))
+	assert.False(g.HasEdge("B", "A"))
+
+	assert.Equal([]string{"B", "D"}, g.Successors("A"))
+	assert.Equal([]string{"B", "D", "F"}, g.Predecessors("E"))
+	assert.Nil(g.Successors("G"))
+	assert.Equal([]string{"C", "lonely node"}, g.Roots())
+}
+
+// The example of day 07
+func exampleGraph() *dag.Graph {
+	g := dag.New()
+	g.AddEdge("C", "A")
+	g.AddEdge("C", "F")
+	g.AddEdge("A", "B")
+	g.AddEdge("A", "D")
+	g.AddEdge("B", "E")
+	g.AddEdge("D", "E")
+	g.AddEdge("F", "E")
+
+	return g
+}
@@ -0,0 +1,101 @@
+package dag
+
+// Whether there is a path from one node to the other, of at least one edge
+func (g *Graph) Reaches(from, to string) bool {
+	fromId, fromExists := g.index[from]
+	toId, toExists := g.index[to]
+	if !fromExists || !toExists {
+		return false
+	}
+
+	return g.reachable(fromId, g.successors)[toId]
+}
+
+// The nodes that can be reached from this node, in the order they were added
+func (g *Graph) Descendants(name string) []string {
+	if id, exists := g.index[name]; exists {
+		return g.namesOf(ids(g.reachable(id, g.successors)))
+	}
+	return nil
+}
+
+// The nodes from which this node can be reached, in the order they were added
+func (g *Graph) Ancestors(name string) []string {
+	if id, exists := g.index[name]; exists {
+		return g.namesOf(ids(g.reachable(id, g.predecessors)))
+	}
+	return nil
+}
+
+// The graph with the fewest edges that has the same reachability: every edge that can be replaced by a
+// longer path is left out. Returns a *CycleError when the graph has a cycle
+func (g *Graph) TransitiveReduction() (*Graph, error) {
+	order, err := g.TopologicalSort(func(a, b string) bool { return g.index[a] < g.index[b] })
+	if err != nil {
+		return nil, err
+	}
+
+	// The nodes reachable through at least one edge, built up from the last node in the order
+	var reach [][]bool = make([][]bool, len(g.names))
+	for idx := len(order) - 1; idx >= 0; idx-- {
+		id := g.index[order[idx]]
+		reach[id] = make([]bool, len(g.names))
+		for _, successor := range g.successors[id] {
+			reach[id][successor] = true
+			for other, reachable := range reach[successor] {
+				reach[id][other] = reach[id][other] || reachable
+			}
+		}
+	}
+
+	var reduced *Graph = New()
+	for _, name := range g.names {
+		reduced.AddNode(name)
+	}
+	for _, edge := range g.edgeOrder {
+		if !g.reachableThroughOther(edge[0], edge[1], reach) {
+			reduced.AddEdge(g.names[edge[0]], g.names[edge[1]])
+		}
+	}
+
+	return reduced, nil
+}
+
+func (g *Graph) reachableThroughOther(from, to int, reach [][]bool) bool {
+	for _, other := range g.successors[from] {
+		if other != to && reach[other][to] {
+			return true
+		}
+	}
+	return false
+}
+
+// The nodes reachable from the start through at least one of the given links
+func (g *Graph) reachable(start int, links [][]int) []bool {
+	var seen []bool = make([]bool, len(g.names))
+	var queue []int = append([]int(nil), links[start]...)
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		queue = append(queue, links[id]...)
+	}
+
+	return seen
+}
+
+func ids(selected []bool) []int {
+	var ids []int
+	for id, isSelected := range selected {
+		if isSelected {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,46 @@
+package dag_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/dag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReachability(t *testing.T) {
+	assert := assert.New(t)
+	g := exampleGraph()
+
+	assert.True(g.Reaches("C", "E"))
+	assert.True(g.Reaches("A", "E"))
+	assert.False(g.Reaches("F", "B"))
+	assert.False(g.Reaches("C", "C"))
+	assert.False(g.Reaches("C", "G"))
+
+	assert.Equal([]string{"A", "F", "B", "D", "E"}, g.Descendants("C"))
+	assert.Equal([]string{"C", "A"}, g.Ancestors("D"))
+	assert.Empty(g.Ancestors("C"))
+	assert.Nil(g.Descendants("G"))
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	g := exampleGraph()
+	g.AddEdge("C", "E")
+	g.AddEdge("A", "E")
+	g.AddEdge("C", "B")
+
+	reduced, err := g.TransitiveReduction()
+
+	assert.NoError(t, err)
+	assert.Equal(t, exampleGraph().Edges(), reduced.Edges())
+	assert.Equal(t, g.Nodes(), reduced.Nodes())
+}
+
+func TestTransitiveReductionWithCycle(t *testing.T) {
+	g := exampleGraph()
+	g.AddEdge("E", "C")
+
+	_, err := g.TransitiveReduction()
+	assert.True(t, errors.Is(err, dag.ErrCycle))
+}
@@ -0,0 +1,109 @@
+package dag
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/search"
+)
+
+var ErrCycle = errors.New("cycle")
+
+// The nodes of a cycle, starting and ending with the same node
+type CycleError struct {
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return "cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+func (e *CycleError) Is(target error) bool {
+	return target == ErrCycle
+}
+
+// Order the nodes so every node comes after its predecessors; of the nodes that are available at the same
+// time, the node for which less holds comes first. Returns a *CycleError when the graph has a cycle
+func (g *Graph) TopologicalSort(less func(a, b string) bool) ([]string, error) {
+	var nrBlocking []int = make([]int, len(g.names))
+	for id := range g.names {
+		nrBlocking[id] = len(g.predecessors[id])
+	}
+
+	available := search.NewHeap(func(a, b int) bool { return less(g.names[a], g.names[b]) })
+	for id := range g.names {
+		if nrBlocking[id] == 0 {
+			available.Push(id)
+		}
+	}
+
+	var order []string = make([]string, 0, len(g.names))
+	for available.Len() > 0 {
+		id := available.Pop()
+		order = append(order, g.names[id])
+
+		for _, successor := range g.successors[id] {
+			if nrBlocking[successor]--; nrBlocking[successor] == 0 {
+				available.Push(successor)
+			}
+		}
+	}
+
+	if len(order) < len(g.names) {
+		return nil, &CycleError{Cycle: g.FindCycle()}
+	}
+
+	return order, nil
+}
+
+// Order the nodes so every node comes after its predecessors, and alphabetically otherwise
+func (g *Graph) LexicographicSort() ([]string, error) {
+	return g.TopologicalSort(func(a, b string) bool { return a < b })
+}
+
+// The nodes of a cycle, starting and ending with the same node; nil when the graph has no cycles
+func (g *Graph) FindCycle() []string {
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+
+	var state []int = make([]int, len(g.names))
+	var path []int
+
+	var visit func(id int) []string
+	visit = func(id int) []string {
+		state[id] = onPath
+		path = append(path, id)
+
+		for _, successor := range g.successors[id] {
+			switch state[successor] {
+			case onPath:
+				var start int = len(path) - 1
+				for path[start] != successor {
+					start--
+				}
+				return g.namesOf(append(append([]int(nil), path[start:]...), successor))
+			case unvisited:
+				if cycle := visit(successor); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		state[id] = done
+		path = path[:len(path)-1]
+		return nil
+	}
+
+	for id := range g.names {
+		if state[id] == unvisited {
+			if cycle := visit(id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package dag_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/dag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLexicographicSort(t *testing.T) {
+	order, err := exampleGraph().LexicographicSort()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"C", "A", "B", "D", "F", "E"}, order)
+}
+
+func TestTopologicalSortWithPriority(t *testing.T) {
+	g := dag.New()
+	g.AddEdge("compile", "link")
+	g.AddEdge("generate", "compile")
+	g.AddEdge("fetch", "compile")
+	g.AddNode("lint")
+
+	// Reversed alphabetical order, as far as the edges allow
+	order, err := g.TopologicalSort(func(a, b string) bool { return a > b })
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"lint", "generate", "fetch", "compile", "link"}, order)
+}
+
+func TestTopologicalSortWithCycle(t *testing.T) {
+	g := exampleGraph()
+	g.AddEdge("E", "G")
+	g.AddEdge("G", "A")
+
+	order, err := g.LexicographicSort()
+
+	assert.Nil(t, order)
+	assert.True(t, errors.Is(err, dag.ErrCycle))
+	assert.EqualError(t, err, "cycle: A -> B -> E -> G -> A")
+
+	var cycleError *dag.CycleError
+	assert.True(t, errors.As(err, &cycleError))
+	assert.Equal(t, []string{"A", "B", "E", "G", "A"}, cycleError.Cycle)
+}
+
+func TestFindCycle(t *testing.T) {
+	assert.Nil(t, exampleGraph().FindCycle())
+
+	g := dag.New()
+	g.AddEdge("a", "a")
+	assert.Equal(t, []string{"a", "a"}, g.FindCycle())
+}
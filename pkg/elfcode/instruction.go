@@ -0,0 +1,184 @@
+package elfcode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
+)
+
+var ErrInvalidRegister = errors.New("invalid register")
+
+// The register file of the device: a fixed number of registers, all starting at 0
+type Registers []int
+
+func NewRegisters(nrRegisters int) Registers {
+	return make(Registers, nrRegisters)
+}
+
+// The registers like "[3, 2, 1, 1]"
+func (r Registers) String() string {
+	var values []string = make([]string, 0, len(r))
+
+	for _, value := range r {
+		values = append(values, strconv.Itoa(value))
+	}
+
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+type Instruction struct {
+	Opcode
+	InputA int
+	InputB int
+	Output int
+}
+
+// The instruction as a line of a program, like "addi 1 2 3"
+func (i Instruction) String() string {
+	return fmt.Sprintf("%s %d %d %d", i.Opcode, i.InputA, i.InputB, i.Output)
+}
+
+// The instruction with its operands decoded, like "addi r1, 2 -> r3" or "seti 5 -> r1"
+func (i Instruction) Disassemble() string {
+	kindA, kindB := i.Opcode.Operands()
+
+	var operands []string = make([]string, 0, 2)
+	for _, operand := range []struct {
+		kind  OperandKind
+		value int
+	}{{kindA, i.InputA}, {kindB, i.InputB}} {
+		switch operand.kind {
+		case Register:
+			operands = append(operands, fmt.Sprintf("r%d", operand.value))
+		case Immediate:
+			operands = append(operands, strconv.Itoa(operand.value))
+		}
+	}
+
+	return fmt.Sprintf("%s %s -> r%d", i.Opcode, strings.Join(operands, ", "), i.Output)
+}
+
+// Execute the instruction on the registers. Fails, without changing the registers, when the instruction
+// refers to a register the device doesn't have
+func (i Instruction) Exec(registers Registers) error {
+	if err := i.checkRegisters(len(registers)); err != nil {
+		return err
+	}
+
+	i.exec(registers)
+	return nil
+}
+
+// Execute the instruction, without checking its registers first
+func (i Instruction) exec(registers Registers) {
+	switch i.Opcode {
+	case Addr:
+		registers[i.Output] = registers[i.InputA] + registers[i.InputB]
+	case Addi:
+		registers[i.Output] = registers[i.InputA] + i.InputB
+	case Mulr:
+		registers[i.Output] = registers[i.InputA] * registers[i.InputB]
+	case Muli:
+		registers[i.Output] = registers[i.InputA] * i.InputB
+	case Banr:
+		registers[i.Output] = registers[i.InputA] & registers[i.InputB]
+	case Bani:
+		registers[i.Output] = registers[i.InputA] & i.InputB
+	case Borr:
+		registers[i.Output] = registers[i.InputA] | registers[i.InputB]
+	case Bori:
+		registers[i.Output] = registers[i.InputA] | i.InputB
+	case Setr:
+		registers[i.Output] = registers[i.InputA]
+	case Seti:
+		registers[i.Output] = i.InputA
+	case Gtir:
+		registers[i.Output] = boolToInt(i.InputA > registers[i.InputB])
+	case Gtri:
+		registers[i.Output] = boolToInt(registers[i.InputA] > i.InputB)
+	case Gtrr:
+		registers[i.Output] = boolToInt(registers[i.InputA] > registers[i.InputB])
+	case Eqir:
+		registers[i.Output] = boolToInt(i.InputA == registers[i.InputB])
+	case Eqri:
+		registers[i.Output] = boolToInt(registers[i.InputA] == i.InputB)
+	case Eqrr:
+		registers[i.Output] = boolToInt(registers[i.InputA] == registers[i.InputB])
+	}
+}
+
+// Whether the opcode is known, and all operands are valid register numbers, whether they are used as such or not.
+// Most instructions are; it is a quick alternative to checkRegisters
+func (i Instruction) fitsRegisters(nrRegisters int) bool {
+	return i.Opcode-Addr <= Eqrr-Addr &&
+		uint(i.InputA) < uint(nrRegisters) && uint(i.InputB) < uint(nrRegisters) && uint(i.Output) < uint(nrRegisters)
+}
+
+// Check every register the instruction refers to, including its output
+func (i Instruction) checkRegisters(nrRegisters int) error {
+	if i.Opcode < Addr || i.Opcode > Eqrr {
+		return fmt.Errorf("%w: %d", ErrUnknownOpcode, uint(i.Opcode))
+	}
+
+	kindA, kindB := i.Opcode.Operands()
+	switch {
+	case kindA == Register && (i.InputA < 0 || i.InputA >= nrRegisters):
+		return i.invalidRegister(i.InputA, nrRegisters)
+	case kindB == Register && (i.InputB < 0 || i.InputB >= nrRegisters):
+		return i.invalidRegister(i.InputB, nrRegisters)
+	case i.Output < 0 || i.Output >= nrRegisters:
+		return i.invalidRegister(i.Output, nrRegisters)
+	}
+
+	return nil
+}
+
+func (i Instruction) invalidRegister(register, nrRegisters int) error {
+	return fmt.Errorf("%s: %w r%d, the device has %d registers", i.Disassemble(), ErrInvalidRegister, register, nrRegisters)
+}
+
+// Parse an instruction like "addi 1 2 3"
+func ParseInstruction(line string) (Instruction, error) {
+	return parseInstruction(line, 0)
+}
+
+func parseInstruction(line string, lineNr int) (Instruction, error) {
+	parts := strings.Split(line, " ")
+	if len(parts) != 4 {
+		return Instruction{}, &utils.ParseError{Line: lineNr, Input: line, Err: fmt.Errorf("expected an opcode and 3 numbers, got %d parts", len(parts))}
+	}
+
+	opcode, err := ParseOpcode(parts[0])
+	if err != nil {
+		return Instruction{}, &utils.ParseError{Line: lineNr, Column: 1, Input: parts[0], Err: ErrUnknownOpcode}
+	}
+
+	var values [3]int
+	var column int = len(parts[0]) + 2
+	for idx, part := range parts[1:] {
+		value, err := utils.ParseIntAt(part, lineNr, column)
+		if err != nil {
+			return Instruction{}, err
+		}
+		values[idx] = value
+		column += len(part) + 1
+	}
+
+	return Instruction{
+		Opcode: opcode,
+		InputA: values[0],
+		InputB: values[1],
+		Output: values[2],
+	}, nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,103 @@
+package elfcode_test
+
+import (
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/elfcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExec(t *testing.T) {
+	// Registers before: [3, 2, 1, 1]; every instruction uses A=2, B=1 and writes to C=3
+	testCases := map[elfcode.Opcode]int{
+		elfcode.Addr: 3,
+		elfcode.Addi: 2,
+		elfcode.Mulr: 2,
+		elfcode.Muli: 1,
+		elfcode.Banr: 0,
+		elfcode.Bani: 1,
+		elfcode.Borr: 3,
+		elfcode.Bori: 1,
+		elfcode.Setr: 1,
+		elfcode.Seti: 2,
+		elfcode.Gtir: 0,
+		elfcode.Gtri: 0,
+		elfcode.Gtrr: 0,
+		elfcode.Eqir: 1,
+		elfcode.Eqri: 1,
+		elfcode.Eqrr: 0,
+	}
+
+	for opcode, expected := range testCases {
+		registers := elfcode.Registers{3, 2, 1, 1}
+		err := elfcode.Instruction{Opcode: opcode, InputA: 2, InputB: 1, Output: 3}.Exec(registers)
+
+		assert.NoError(t, err)
+
+		assert.Equal(t, elfcode.Registers{3, 2, 1, expected}, registers, opcode.String())
+	}
+}
+
+func TestExecInvalid(t *testing.T) {
+	testCases := map[string]elfcode.Instruction{
+		"addr r1, r4 -> r3: invalid register r4, the device has 4 registers":  {Opcode: elfcode.Addr, InputA: 1, InputB: 4, Output: 3},
+		"gtir 9, r-1 -> r3: invalid register r-1, the device has 4 registers": {Opcode: elfcode.Gtir, InputA: 9, InputB: -1, Output: 3},
+		"seti 9 -> r4: invalid register r4, the device has 4 registers":       {Opcode: elfcode.Seti, InputA: 9, InputB: 9, Output: 4},
+		"unknown opcode: 42": {Opcode: 42},
+	}
+
+	for expectedError, instruction := range testCases {
+		registers := elfcode.Registers{3, 2, 1, 1}
+		err := instruction.Exec(registers)
+
+		assert.EqualError(t, err, expectedError)
+		assert.Equal(t, elfcode.Registers{3, 2, 1, 1}, registers)
+	}
+
+	// Immediate values are never registers
+	assert.NoError(t, elfcode.Instruction{Opcode: elfcode.Muli, InputA: 1, InputB: 9, Output: 3}.Exec(elfcode.Registers{3, 2, 1, 1}))
+}
+
+func TestNewRegisters(t *testing.T) {
+	assert.Equal(t, elfcode.Registers{0, 0, 0, 0, 0, 0}, elfcode.NewRegisters(6))
+}
+
+func TestParseInstruction(t *testing.T) {
+	instruction, err := elfcode.ParseInstruction("addi 1 2 3")
+
+	assert.NoError(t, err)
+	assert.Equal(t, elfcode.Instruction{Opcode: elfcode.Addi, InputA: 1, InputB: 2, Output: 3}, instruction)
+	assert.Equal(t, "addi 1 2 3", instruction.String())
+}
+
+func TestDisassemble(t *testing.T) {
+	testCases := map[string]elfcode.Instruction{
+		"addr r1, r2 -> r3": {Opcode: elfcode.Addr, InputA: 1, InputB: 2, Output: 3},
+		"muli r1, 2 -> r3":  {Opcode: elfcode.Muli, InputA: 1, InputB: 2, Output: 3},
+		"gtir 1, r2 -> r3":  {Opcode: elfcode.Gtir, InputA: 1, InputB: 2, Output: 3},
+		"setr r1 -> r3":     {Opcode: elfcode.Setr, InputA: 1, InputB: 2, Output: 3},
+		"seti 1 -> r3":      {Opcode: elfcode.Seti, InputA: 1, InputB: 2, Output: 3},
+	}
+
+	for expected, instruction := range testCases {
+		assert.Equal(t, expected, instruction.Disassemble())
+	}
+}
+
+func TestRegistersString(t *testing.T) {
+	assert.Equal(t, "[3, 2, 1, 1, 0, 9]", elfcode.Registers{3, 2, 1, 1, 0, 9}.String())
+}
+
+func TestParseInstructionInvalid(t *testing.T) {
+	testCases := map[string]string{
+		"parse 'addx': unknown opcode":                                        "addx 1 2 3",
+		"parse 'x': invalid syntax":                                           "addi 1 x 3",
+		"parse 'addi 1 2': expected an opcode and 3 numbers, got 3 parts":     "addi 1 2",
+		"parse 'addi 1 2 3 4': expected an opcode and 3 numbers, got 5 parts": "addi 1 2 3 4",
+	}
+
+	for expectedError, line := range testCases {
+		_, err := elfcode.ParseInstruction(line)
+		assert.EqualError(t, err, expectedError)
+	}
+}
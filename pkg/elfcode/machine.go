@@ -0,0 +1,155 @@
+package elfcode
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+var ErrInstructionLimit = errors.New("instruction limit reached")
+
+// A device running a program
+type Machine struct {
+	Program
+	Registers Registers
+	IP        int // The instruction pointer: the index of the next instruction
+	NrSteps   int // The number of instructions executed so far
+	Limit     int // Stop running after this many instructions; 0 for no limit
+
+	Tracer func(TraceStep) // Called after every executed instruction, when set
+
+	checked bool // Whether the registers of the program have been checked
+}
+
+// A single executed instruction, with the registers before and after
+type TraceStep struct {
+	IP          int
+	Instruction Instruction
+	Before      Registers
+	After       Registers
+}
+
+func (s TraceStep) String() string {
+	return fmt.Sprintf("%3d: %-22s %v -> %v", s.IP, s.Instruction.Disassemble(), s.Before, s.After)
+}
+
+// A tracer writing every step as a line
+func WriteTrace(w io.Writer) func(TraceStep) {
+	return func(step TraceStep) {
+		fmt.Fprintln(w, step)
+	}
+}
+
+func NewMachine(program Program, nrRegisters int) *Machine {
+	return &Machine{
+		Program:   program,
+		Registers: NewRegisters(nrRegisters),
+	}
+}
+
+// The machine halts when the instruction pointer points outside the program
+func (m *Machine) Halted() bool {
+	return m.IP < 0 || m.IP >= len(m.Instructions)
+}
+
+// The instruction that will be executed by the next step
+func (m *Machine) Current() Instruction {
+	return m.Instructions[m.IP]
+}
+
+// Execute a single instruction. Returns false, without executing anything, when the machine has halted.
+// Before the first step, the whole program is checked: it fails when it refers to a register the device doesn't have.
+// As the registers and the program can be changed between steps, every step checks its own instruction again
+func (m *Machine) Step() (bool, error) {
+	if !m.checked {
+		if err := m.check(); err != nil {
+			return false, err
+		}
+		m.checked = true
+	}
+
+	if m.Halted() {
+		return false, nil
+	}
+
+	if !m.Instructions[m.IP].fitsRegisters(len(m.Registers)) || (m.IPBound && uint(m.IPRegister) >= uint(len(m.Registers))) {
+		if err := m.checkStep(); err != nil {
+			return false, err
+		}
+	}
+
+	if m.IPBound {
+		m.Registers[m.IPRegister] = m.IP
+	}
+
+	var before Registers
+	if m.Tracer != nil {
+		before = slices.Clone(m.Registers)
+	}
+
+	m.Instructions[m.IP].exec(m.Registers)
+	m.NrSteps++
+
+	if m.Tracer != nil {
+		m.Tracer(TraceStep{IP: m.IP, Instruction: m.Instructions[m.IP], Before: before, After: slices.Clone(m.Registers)})
+	}
+
+	if m.IPBound {
+		m.IP = m.Registers[m.IPRegister]
+	}
+	m.IP++
+
+	return true, nil
+}
+
+func (m *Machine) check() error {
+	if err := m.checkIPRegister(); err != nil {
+		return err
+	}
+
+	for idx, instruction := range m.Instructions {
+		if err := instruction.checkRegisters(len(m.Registers)); err != nil {
+			return fmt.Errorf("instruction %d: %w", idx, err)
+		}
+	}
+
+	return nil
+}
+
+// Check the current instruction, and the register bound to the instruction pointer
+func (m *Machine) checkStep() error {
+	if err := m.checkIPRegister(); err != nil {
+		return err
+	}
+	if err := m.Instructions[m.IP].checkRegisters(len(m.Registers)); err != nil {
+		return fmt.Errorf("instruction %d: %w", m.IP, err)
+	}
+
+	return nil
+}
+
+func (m *Machine) checkIPRegister() error {
+	var nrRegisters int = len(m.Registers)
+
+	if m.IPBound && (m.IPRegister < 0 || m.IPRegister >= nrRegisters) {
+		return fmt.Errorf("#ip %d: %w r%d, the device has %d registers", m.IPRegister, ErrInvalidRegister, m.IPRegister, nrRegisters)
+	}
+
+	return nil
+}
+
+// Run until the machine halts, or until the instruction limit is reached
+func (m *Machine) Run() error {
+	for m.Limit == 0 || m.NrSteps < m.Limit {
+		if ok, err := m.Step(); !ok {
+			return err
+		}
+	}
+
+	if m.Halted() {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %d instructions", ErrInstructionLimit, m.Limit)
+}
@@ -0,0 +1,122 @@
+package elfcode_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/elfcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMachineStep(t *testing.T) {
+	program, _ := elfcode.ParseProgram(testProgram())
+	machine := elfcode.NewMachine(program, 6)
+
+	assert.Equal(t, elfcode.Seti, machine.Current().Opcode)
+	ok, err := machine.Step()
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, elfcode.Registers{0, 5, 0, 0, 0, 0}, machine.Registers)
+	assert.Equal(t, 1, machine.IP)
+
+	machine.Step()
+	machine.Step()
+
+	// 'addi 0 1 0' jumps over the next instruction
+	assert.Equal(t, elfcode.Registers{3, 5, 6, 0, 0, 0}, machine.Registers)
+	assert.Equal(t, 4, machine.IP)
+	assert.Equal(t, 3, machine.NrSteps)
+}
+
+func TestMachineRun(t *testing.T) {
+	program, _ := elfcode.ParseProgram(testProgram())
+	machine := elfcode.NewMachine(program, 6)
+
+	assert.NoError(t, machine.Run())
+	assert.True(t, machine.Halted())
+	ok, err := machine.Step()
+	assert.False(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, elfcode.Registers{6, 5, 6, 0, 0, 9}, machine.Registers)
+	assert.Equal(t, 7, machine.IP)
+	assert.Equal(t, 5, machine.NrSteps)
+}
+
+func TestMachineRunWithoutIP(t *testing.T) {
+	program, _ := elfcode.ParseProgram([]string{"seti 5 0 1", "addi 1 3 0"})
+	machine := elfcode.NewMachine(program, 4)
+
+	assert.NoError(t, machine.Run())
+	assert.Equal(t, elfcode.Registers{8, 5, 0, 0}, machine.Registers)
+
+	// The zero value of a program doesn't bind the instruction pointer to r0
+	machine = elfcode.NewMachine(elfcode.Program{Instructions: program.Instructions}, 4)
+
+	assert.NoError(t, machine.Run())
+	assert.Equal(t, elfcode.Registers{8, 5, 0, 0}, machine.Registers)
+}
+
+func TestMachineTrace(t *testing.T) {
+	program, _ := elfcode.ParseProgram(testProgram())
+	machine := elfcode.NewMachine(program, 6)
+
+	var trace strings.Builder
+	machine.Tracer = elfcode.WriteTrace(&trace)
+	machine.Run()
+
+	assert.Equal(t, strings.Join([]string{
+		"  0: seti 5 -> r1           [0, 0, 0, 0, 0, 0] -> [0, 5, 0, 0, 0, 0]",
+		"  1: seti 6 -> r2           [1, 5, 0, 0, 0, 0] -> [1, 5, 6, 0, 0, 0]",
+		"  2: addi r0, 1 -> r0       [2, 5, 6, 0, 0, 0] -> [3, 5, 6, 0, 0, 0]",
+		"  4: setr r1 -> r0          [4, 5, 6, 0, 0, 0] -> [5, 5, 6, 0, 0, 0]",
+		"  6: seti 9 -> r5           [6, 5, 6, 0, 0, 0] -> [6, 5, 6, 0, 0, 9]",
+		"",
+	}, "\n"), trace.String())
+}
+
+func TestMachineInvalidRegister(t *testing.T) {
+	program, _ := elfcode.ParseProgram(testProgram())
+
+	// The example needs 6 registers
+	err := elfcode.NewMachine(program, 5).Run()
+	assert.ErrorIs(t, err, elfcode.ErrInvalidRegister)
+	assert.EqualError(t, err, "instruction 6: seti 9 -> r5: invalid register r5, the device has 5 registers")
+
+	program.IPRegister = 6
+	err = elfcode.NewMachine(program, 6).Run()
+	assert.EqualError(t, err, "#ip 6: invalid register r6, the device has 6 registers")
+}
+
+func TestMachineChangedBetweenSteps(t *testing.T) {
+	program, _ := elfcode.ParseProgram([]string{"seti 5 0 1", "addi 1 3 2"})
+	machine := elfcode.NewMachine(program, 4)
+	machine.Step()
+
+	machine.Registers = machine.Registers[:2]
+	_, err := machine.Step()
+	assert.EqualError(t, err, "instruction 1: addi r1, 3 -> r2: invalid register r2, the device has 2 registers")
+
+	machine.Registers = elfcode.NewRegisters(4)
+	machine.Instructions[1].Output = 7
+	_, err = machine.Step()
+	assert.ErrorIs(t, err, elfcode.ErrInvalidRegister)
+
+	machine.Instructions[1].Output = 2
+	machine.IPBound, machine.IPRegister = true, 4
+	_, err = machine.Step()
+	assert.EqualError(t, err, "#ip 4: invalid register r4, the device has 4 registers")
+}
+
+func TestMachineInstructionLimit(t *testing.T) {
+	// Jumps back to the first instruction forever
+	program, _ := elfcode.ParseProgram([]string{"#ip 1", "addi 0 1 0", "seti -1 0 1"})
+	machine := elfcode.NewMachine(program, 2)
+	machine.Limit = 10
+
+	err := machine.Run()
+
+	assert.ErrorIs(t, err, elfcode.ErrInstructionLimit)
+	assert.EqualError(t, err, "instruction limit reached: 10 instructions")
+	assert.Equal(t, 10, machine.NrSteps)
+	assert.Equal(t, 5, machine.Registers[0])
+}
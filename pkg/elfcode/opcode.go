@@ -0,0 +1,99 @@
+// Package elfcode runs programs on the instruction set of the wrist device, as used by days 16, 19 and 21
+package elfcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownOpcode = errors.New("unknown opcode")
+
+type Opcode uint
+
+const (
+	Addr Opcode = iota + 1
+	Addi
+	Muli
+	Mulr
+	Banr
+	Bani
+	Borr
+	Bori
+	Setr
+	Seti
+	Gtir
+	Gtri
+	Gtrr
+	Eqir
+	Eqri
+	Eqrr
+)
+
+var mnemonics = map[Opcode]string{
+	Addr: "addr", Addi: "addi",
+	Muli: "muli", Mulr: "mulr",
+	Banr: "banr", Bani: "bani",
+	Borr: "borr", Bori: "bori",
+	Setr: "setr", Seti: "seti",
+	Gtir: "gtir", Gtri: "gtri", Gtrr: "gtrr",
+	Eqir: "eqir", Eqri: "eqri", Eqrr: "eqrr",
+}
+
+func AllOpcodes() []Opcode {
+	return []Opcode{
+		Addr, Addi,
+		Muli, Mulr,
+		Banr, Bani,
+		Borr, Bori,
+		Setr, Seti,
+		Gtir, Gtri, Gtrr,
+		Eqir, Eqri, Eqrr,
+	}
+}
+
+// The mnemonic of the opcode, like "addi"
+func (o Opcode) String() string {
+	if mnemonic, ok := mnemonics[o]; ok {
+		return mnemonic
+	}
+
+	return fmt.Sprintf("opcode(%d)", uint(o))
+}
+
+// Find the opcode by its mnemonic
+func ParseOpcode(mnemonic string) (Opcode, error) {
+	for opcode, current := range mnemonics {
+		if current == mnemonic {
+			return opcode, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w '%s'", ErrUnknownOpcode, mnemonic)
+}
+
+// How an instruction uses its inputs
+type OperandKind uint
+
+const (
+	Ignored OperandKind = iota
+	Register
+	Immediate
+)
+
+// How the opcode uses inputs A and B; the output is always a register
+func (o Opcode) Operands() (a, b OperandKind) {
+	switch o {
+	case Addr, Mulr, Banr, Borr, Gtrr, Eqrr:
+		return Register, Register
+	case Addi, Muli, Bani, Bori, Gtri, Eqri:
+		return Register, Immediate
+	case Gtir, Eqir:
+		return Immediate, Register
+	case Setr:
+		return Register, Ignored
+	case Seti:
+		return Immediate, Ignored
+	default:
+		return Ignored, Ignored
+	}
+}
@@ -0,0 +1,29 @@
+package elfcode_test
+
+import (
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/elfcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpcodeMnemonics(t *testing.T) {
+	assert.Len(t, elfcode.AllOpcodes(), 16)
+
+	for _, opcode := range elfcode.AllOpcodes() {
+		parsed, err := elfcode.ParseOpcode(opcode.String())
+
+		assert.NoError(t, err)
+		assert.Equal(t, opcode, parsed)
+	}
+
+	assert.Equal(t, "gtir", elfcode.Gtir.String())
+	assert.Equal(t, "opcode(42)", elfcode.Opcode(42).String())
+}
+
+func TestParseOpcodeUnknown(t *testing.T) {
+	_, err := elfcode.ParseOpcode("addx")
+
+	assert.ErrorIs(t, err, elfcode.ErrUnknownOpcode)
+	assert.EqualError(t, err, "unknown opcode 'addx'")
+}
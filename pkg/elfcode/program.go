@@ -0,0 +1,85 @@
+package elfcode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
+)
+
+// A program, optionally with a register bound to the instruction pointer. The zero value is unbound
+type Program struct {
+	IPBound      bool
+	IPRegister   int // The register bound to the instruction pointer; only used when IPBound
+	Instructions []Instruction
+}
+
+// Parse a program like:
+//
+//	#ip 0
+//	seti 5 0 1
+//
+// The '#ip' line is optional. Errors point to the offending line, counting from 1
+func ParseProgram(lines []string) (Program, error) {
+	var program Program = Program{
+		Instructions: make([]Instruction, 0, len(lines)),
+	}
+
+	for idx, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		if ipRegister, found := strings.CutPrefix(line, "#ip "); found {
+			if len(program.Instructions) > 0 || program.IPBound {
+				return Program{}, &utils.ParseError{Line: idx + 1, Input: line, Err: errors.New("'#ip' must be the first line")}
+			}
+
+			value, err := utils.ParseIntAt(ipRegister, idx+1, len("#ip ")+1)
+			if err != nil {
+				return Program{}, err
+			}
+			program.IPBound, program.IPRegister = true, value
+			continue
+		}
+
+		instruction, err := parseInstruction(line, idx+1)
+		if err != nil {
+			return Program{}, err
+		}
+		program.Instructions = append(program.Instructions, instruction)
+	}
+
+	return program, nil
+}
+
+// The program in the same format as it was parsed from
+func (p Program) String() string {
+	var lines []string = make([]string, 0, len(p.Instructions)+1)
+
+	if p.IPBound {
+		lines = append(lines, "#ip "+strconv.Itoa(p.IPRegister))
+	}
+	for _, instruction := range p.Instructions {
+		lines = append(lines, instruction.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// The program with decoded operands, one numbered instruction per line
+func (p Program) Disassemble() []string {
+	var lines []string = make([]string, 0, len(p.Instructions))
+
+	for idx, instruction := range p.Instructions {
+		line := fmt.Sprintf("%3d: %s", idx, instruction.Disassemble())
+		if p.IPBound && instruction.Output == p.IPRegister {
+			line += " (jump)"
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -0,0 +1,68 @@
+package elfcode_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/elfcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProgram(t *testing.T) {
+	program, err := elfcode.ParseProgram(testProgram())
+
+	assert.NoError(t, err)
+	assert.True(t, program.IPBound)
+	assert.Equal(t, 0, program.IPRegister)
+	assert.Len(t, program.Instructions, 7)
+	assert.Equal(t, elfcode.Instruction{Opcode: elfcode.Setr, InputA: 1, InputB: 0, Output: 0}, program.Instructions[4])
+	assert.Equal(t, strings.Join(testProgram(), "\n"), program.String())
+}
+
+func TestParseProgramWithoutIP(t *testing.T) {
+	program, err := elfcode.ParseProgram([]string{"seti 5 0 1", "", "addi 1 1 1"})
+
+	assert.NoError(t, err)
+	assert.False(t, program.IPBound)
+	assert.Len(t, program.Instructions, 2)
+}
+
+func TestProgramDisassemble(t *testing.T) {
+	program, _ := elfcode.ParseProgram(testProgram())
+
+	assert.Equal(t, []string{
+		"  0: seti 5 -> r1",
+		"  1: seti 6 -> r2",
+		"  2: addi r0, 1 -> r0 (jump)",
+		"  3: addr r1, r2 -> r3",
+		"  4: setr r1 -> r0 (jump)",
+		"  5: seti 8 -> r4",
+		"  6: seti 9 -> r5",
+	}, program.Disassemble())
+}
+
+func TestParseProgramInvalid(t *testing.T) {
+	testCases := map[string][]string{
+		"line 1, column 5: parse 'x': invalid syntax":         {"#ip x"},
+		"line 2, column 1: parse 'sett': unknown opcode":      {"#ip 0", "sett 5 0 1"},
+		"line 2: parse '#ip 1': '#ip' must be the first line": {"seti 5 0 1", "#ip 1"},
+	}
+
+	for expectedError, lines := range testCases {
+		_, err := elfcode.ParseProgram(lines)
+		assert.EqualError(t, err, expectedError)
+	}
+}
+
+func testProgram() []string {
+	return []string{
+		"#ip 0",
+		"seti 5 0 1",
+		"seti 6 0 2",
+		"addi 0 1 0",
+		"addr 1 2 3",
+		"setr 1 0 0",
+		"seti 8 0 4",
+		"seti 9 0 5",
+	}
+}
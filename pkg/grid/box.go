@@ -0,0 +1,65 @@
+package grid
+
+// A rectangle on the grid, including both Min and Max
+type Box struct {
+	Min Point
+	Max Point
+}
+
+// The smallest box containing all the points
+func BoundingBox(points ...Point) Box {
+	if len(points) == 0 {
+		return Box{}
+	}
+
+	var box Box = Box{Min: points[0], Max: points[0]}
+	for _, point := range points[1:] {
+		box = box.Include(point)
+	}
+
+	return box
+}
+
+// The smallest box containing both this box and the point
+func (b Box) Include(p Point) Box {
+	return Box{
+		Min: Point{X: min(b.Min.X, p.X), Y: min(b.Min.Y, p.Y)},
+		Max: Point{X: max(b.Max.X, p.X), Y: max(b.Max.Y, p.Y)},
+	}
+}
+
+// Grow the box on all sides; a negative margin shrinks it
+func (b Box) Expand(margin int) Box {
+	return Box{
+		Min: Point{X: b.Min.X - margin, Y: b.Min.Y - margin},
+		Max: Point{X: b.Max.X + margin, Y: b.Max.Y + margin},
+	}
+}
+
+func (b Box) Contains(p Point) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X &&
+		p.Y >= b.Min.Y && p.Y <= b.Max.Y
+}
+
+// Is the point on the outer border of the box
+func (b Box) OnEdge(p Point) bool {
+	return b.Contains(p) &&
+		(p.X == b.Min.X || p.X == b.Max.X || p.Y == b.Min.Y || p.Y == b.Max.Y)
+}
+
+func (b Box) Width() int {
+	return b.Max.X - b.Min.X + 1
+}
+
+func (b Box) Height() int {
+	return b.Max.Y - b.Min.Y + 1
+}
+
+// Call fn for every point in the box, row by row
+func (b Box) Each(fn func(p Point)) {
+	for y := b.Min.Y; y <= b.Max.Y; y++ {
+		for x := b.Min.X; x <= b.Max.X; x++ {
+			fn(Point{X: x, Y: y})
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package grid_test
+
+import (
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/grid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundingBox(t *testing.T) {
+	assert := assert.New(t)
+
+	box := grid.BoundingBox(grid.Pt(1, 6), grid.Pt(8, 3), grid.Pt(3, 9), grid.Pt(5, 1))
+
+	assert.Equal(grid.Box{Min: grid.Pt(1, 1), Max: grid.Pt(8, 9)}, box)
+	assert.Equal(8, box.Width())
+	assert.Equal(9, box.Height())
+	assert.True(box.Contains(grid.Pt(1, 9)))
+	assert.False(box.Contains(grid.Pt(0, 5)))
+	assert.True(box.OnEdge(grid.Pt(8, 5)))
+	assert.False(box.OnEdge(grid.Pt(7, 5)))
+	assert.False(box.OnEdge(grid.Pt(9, 5)))
+	assert.Equal(grid.Box{Min: grid.Pt(0, 0), Max: grid.Pt(9, 10)}, box.Expand(1))
+	assert.Equal(grid.Box{}, grid.BoundingBox())
+}
+
+func TestBoxEach(t *testing.T) {
+	var points []grid.Point
+
+	grid.Box{Min: grid.Pt(-1, 0), Max: grid.Pt(0, 1)}.Each(func(p grid.Point) {
+		points = append(points, p)
+	})
+
+	assert.Equal(t, []grid.Point{{-1, 0}, {0, 0}, {-1, 1}, {0, 1}}, points)
+}
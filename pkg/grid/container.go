@@ -0,0 +1,55 @@
+package grid
+
+// A grid that only stores the locations that were set
+type Sparse[T any] map[Point]T
+
+// The bounding box of all the set locations
+func (s Sparse[T]) Bounds() Box {
+	var points []Point = make([]Point, 0, len(s))
+	for point := range s {
+		points = append(points, point)
+	}
+
+	return BoundingBox(points...)
+}
+
+// A grid that stores a value for every location in its box
+type Dense[T any] struct {
+	box   Box
+	cells []T
+}
+
+func NewDense[T any](box Box) *Dense[T] {
+	return &Dense[T]{
+		box:   box,
+		cells: make([]T, box.Width()*box.Height()),
+	}
+}
+
+func (d *Dense[T]) Bounds() Box {
+	return d.box
+}
+
+// Get the value at the location; the zero-value when the location is outside the box
+func (d *Dense[T]) Get(p Point) T {
+	if !d.box.Contains(p) {
+		var zero T
+		return zero
+	}
+
+	return d.cells[d.index(p)]
+}
+
+// Set the value at the location. A location outside the box is ignored, and reported by returning false
+func (d *Dense[T]) Set(p Point, value T) bool {
+	if !d.box.Contains(p) {
+		return false
+	}
+
+	d.cells[d.index(p)] = value
+	return true
+}
+
+func (d *Dense[T]) index(p Point) int {
+	return (p.Y-d.box.Min.Y)*d.box.Width() + (p.X - d.box.Min.X)
+}
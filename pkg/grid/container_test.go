@@ -0,0 +1,46 @@
+package grid_test
+
+import (
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/grid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDense(t *testing.T) {
+	assert := assert.New(t)
+
+	dense := grid.NewDense[int](grid.Box{Min: grid.Pt(-2, -2), Max: grid.Pt(2, 2)})
+
+	assert.True(dense.Set(grid.Pt(-2, 1), 42))
+	assert.False(dense.Set(grid.Pt(3, 0), 1))
+	assert.Equal(42, dense.Get(grid.Pt(-2, 1)))
+	assert.Equal(0, dense.Get(grid.Pt(1, -2)))
+	assert.Equal(0, dense.Get(grid.Pt(3, 0)))
+}
+
+func TestSparseBounds(t *testing.T) {
+	sparse := grid.Sparse[bool]{
+		grid.Pt(4, -1): true,
+		grid.Pt(-3, 7): true,
+	}
+
+	assert.Equal(t, grid.Box{Min: grid.Pt(-3, -1), Max: grid.Pt(4, 7)}, sparse.Bounds())
+}
+
+func TestParseDense(t *testing.T) {
+	dense := grid.ParseDense([]string{"#.#", "."})
+
+	assert.Equal(t, grid.Box{Max: grid.Pt(2, 1)}, dense.Bounds())
+	assert.Equal(t, '#', dense.Get(grid.Pt(2, 0)))
+	assert.Equal(t, '.', dense.Get(grid.Pt(0, 1)))
+	assert.Equal(t, ' ', dense.Get(grid.Pt(2, 1)))
+}
+
+func TestParseSparse(t *testing.T) {
+	sparse := grid.ParseSparse([]string{`/-\`, `| `}, func(char rune) bool { return char == ' ' })
+
+	assert.Len(t, sparse, 4)
+	assert.Equal(t, '\\', sparse[grid.Pt(2, 0)])
+	assert.NotContains(t, sparse, grid.Pt(1, 1))
+}
@@ -0,0 +1,43 @@
+package grid
+
+type (
+	Direction uint
+	Turn      int
+)
+
+const (
+	Up Direction = iota
+	Right
+	Down
+	Left
+)
+
+const (
+	TurnLeft    Turn = -1
+	TurnForward Turn = 0
+	TurnRight   Turn = 1
+	TurnAround  Turn = 2
+)
+
+func AllDirections() [4]Direction {
+	return [4]Direction{Up, Right, Down, Left}
+}
+
+// The direction after making the turn
+func (d Direction) Turn(t Turn) Direction {
+	return Direction((int(d) + int(t) + 4) % 4)
+}
+
+// The step to take when moving a single location in the direction
+func (d Direction) Vector() Point {
+	return [4]Point{
+		Up:    {0, -1},
+		Right: {1, 0},
+		Down:  {0, 1},
+		Left:  {-1, 0},
+	}[d%4]
+}
+
+func (d Direction) String() string {
+	return [4]string{"Up", "Right", "Down", "Left"}[d%4]
+}
@@ -0,0 +1,42 @@
+package grid
+
+// Parse a grid of characters, with the first character of the first line at 0,0.
+// Lines shorter than the longest line are padded with spaces
+func ParseDense(lines []string) *Dense[rune] {
+	var width int = 0
+	var runeLines [][]rune = make([][]rune, 0, len(lines))
+	for _, line := range lines {
+		runes := []rune(line)
+		runeLines = append(runeLines, runes)
+		width = max(width, len(runes))
+	}
+
+	var dense *Dense[rune] = NewDense[rune](Box{Max: Point{X: width - 1, Y: len(lines) - 1}})
+	for y, runes := range runeLines {
+		for x := 0; x < width; x++ {
+			var char rune = ' '
+			if x < len(runes) {
+				char = runes[x]
+			}
+			dense.Set(Point{X: x, Y: y}, char)
+		}
+	}
+
+	return dense
+}
+
+// Parse a grid of characters, with the first character of the first line at 0,0.
+// Characters for which ignore returns true are left out
+func ParseSparse(lines []string, ignore func(char rune) bool) Sparse[rune] {
+	var sparse Sparse[rune] = make(Sparse[rune])
+
+	for y, line := range lines {
+		for x, char := range []rune(line) {
+			if ignore == nil || !ignore(char) {
+				sparse[Point{X: x, Y: y}] = char
+			}
+		}
+	}
+
+	return sparse
+}
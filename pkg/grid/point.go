@@ -0,0 +1,69 @@
+// Package grid holds the 2D points, directions and grids shared by the puzzles
+package grid
+
+import "fmt"
+
+// A location on a 2D grid. Y grows downwards, like the lines of an input
+type Point struct {
+	X int
+	Y int
+}
+
+func Pt(x, y int) Point {
+	return Point{X: x, Y: y}
+}
+
+func (p Point) Add(other Point) Point {
+	return Point{X: p.X + other.X, Y: p.Y + other.Y}
+}
+
+func (p Point) Sub(other Point) Point {
+	return Point{X: p.X - other.X, Y: p.Y - other.Y}
+}
+
+func (p Point) Mul(factor int) Point {
+	return Point{X: p.X * factor, Y: p.Y * factor}
+}
+
+func (p Point) ManhattanDistance(other Point) int {
+	return abs(p.X-other.X) + abs(p.Y-other.Y)
+}
+
+// The 4 orthogonal neighbours, clockwise starting above the point
+func (p Point) Neighbours4() [4]Point {
+	var neighbours [4]Point
+	for idx, direction := range AllDirections() {
+		neighbours[idx] = p.Add(direction.Vector())
+	}
+
+	return neighbours
+}
+
+// The 8 orthogonal and diagonal neighbours, clockwise starting above the point
+func (p Point) Neighbours8() [8]Point {
+	var neighbours [8]Point
+	for idx, vector := range diagonalVectors() {
+		neighbours[idx] = p.Add(vector)
+	}
+
+	return neighbours
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("%d,%d", p.X, p.Y)
+}
+
+func diagonalVectors() [8]Point {
+	return [8]Point{
+		{0, -1}, {1, -1}, {1, 0}, {1, 1},
+		{0, 1}, {-1, 1}, {-1, 0}, {-1, -1},
+	}
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
@@ -0,0 +1,57 @@
+package grid_test
+
+import (
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/grid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointArithmetic(t *testing.T) {
+	p := grid.Pt(3, -2)
+
+	assert.Equal(t, grid.Pt(4, 0), p.Add(grid.Pt(1, 2)))
+	assert.Equal(t, grid.Pt(2, -4), p.Sub(grid.Pt(1, 2)))
+	assert.Equal(t, grid.Pt(9, -6), p.Mul(3))
+	assert.Equal(t, 7, p.ManhattanDistance(grid.Pt(-1, 1)))
+	assert.Equal(t, "3,-2", p.String())
+}
+
+func TestNeighbours(t *testing.T) {
+	p := grid.Pt(1, 1)
+
+	assert.Equal(t, [4]grid.Point{{1, 0}, {2, 1}, {1, 2}, {0, 1}}, p.Neighbours4())
+	assert.Equal(t, [8]grid.Point{
+		{1, 0}, {2, 0}, {2, 1}, {2, 2},
+		{1, 2}, {0, 2}, {0, 1}, {0, 0},
+	}, p.Neighbours8())
+}
+
+func TestDirectionTurn(t *testing.T) {
+	testCases := map[grid.Direction]map[grid.Turn]grid.Direction{
+		grid.Up: {
+			grid.TurnLeft:    grid.Left,
+			grid.TurnForward: grid.Up,
+			grid.TurnRight:   grid.Right,
+			grid.TurnAround:  grid.Down,
+		},
+		grid.Left: {
+			grid.TurnLeft:   grid.Down,
+			grid.TurnRight:  grid.Up,
+			grid.TurnAround: grid.Right,
+		},
+	}
+
+	for direction, turns := range testCases {
+		for turn, expectedDirection := range turns {
+			assert.Equal(t, expectedDirection, direction.Turn(turn), direction.String())
+		}
+	}
+}
+
+func TestDirectionVector(t *testing.T) {
+	assert.Equal(t, grid.Pt(0, -1), grid.Up.Vector())
+	assert.Equal(t, grid.Pt(1, 0), grid.Right.Vector())
+	assert.Equal(t, grid.Pt(0, 1), grid.Down.Vector())
+	assert.Equal(t, grid.Pt(-1, 0), grid.Left.Vector())
+}
@@ -0,0 +1,21 @@
+package register
+
+import "fmt"
+
+// The answer to a part of a puzzle
+type Answer interface {
+	String() string
+}
+
+type (
+	IntAnswer        int
+	StringAnswer     string
+	CoordinateAnswer struct {
+		X int
+		Y int
+	}
+)
+
+func (a IntAnswer) String() string        { return fmt.Sprintf("%d", int(a)) }
+func (a StringAnswer) String() string     { return string(a) }
+func (a CoordinateAnswer) String() string { return fmt.Sprintf("%d,%d", a.X, a.Y) }
@@ -0,0 +1,209 @@
+package register
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// What is known about the answer of a part: the confirmed answer and the guesses that were rejected
+type Expectation struct {
+	Answer  string   // The confirmed answer; empty when the part is not solved yet
+	TooLow  []string // Rejected, because the answer is higher
+	TooHigh []string // Rejected, because the answer is lower
+	Wrong   []string // Rejected, without a hint
+}
+
+type Expectations map[Part]Expectation
+
+type Verdict uint
+
+const (
+	VerdictUnverified Verdict = iota // No confirmed answer, and not known to be wrong
+	VerdictCorrect                   // Equal to the confirmed answer
+	VerdictRegression                // Differs from the confirmed answer
+	VerdictTooLow                    // Not higher than an answer known to be too low
+	VerdictTooHigh                   // Not lower than an answer known to be too high
+	VerdictWrong                     // Equal to an answer known to be wrong
+	VerdictFailed                    // The part returned an error
+)
+
+func (v Verdict) String() string {
+	return map[Verdict]string{
+		VerdictUnverified: "UNVERIFIED",
+		VerdictCorrect:    "OK",
+		VerdictRegression: "REGRESSION",
+		VerdictTooLow:     "TOO LOW",
+		VerdictTooHigh:    "TOO HIGH",
+		VerdictWrong:      "WRONG",
+		VerdictFailed:     "FAILED",
+	}[v]
+}
+
+// Is the verdict a reason to fail the verification
+func (v Verdict) IsFailure() bool {
+	return v != VerdictUnverified && v != VerdictCorrect
+}
+
+// The verified answer of a single part
+type PartVerification struct {
+	PartResult
+	Expected string
+	Verdict  Verdict
+}
+
+type Verification struct {
+	Day   string
+	Parts []PartVerification
+	Err   error
+}
+
+// Execute a day and compare its answers with the expectations of that day
+func VerifyDay(nrDay string) Verification {
+	return VerifyResult(ExecDay(nrDay))
+}
+
+// Compare the answers of an executed day with the expectations of that day
+func VerifyResult(result Result) Verification {
+	var verification Verification = Verification{Day: result.Day}
+
+	expectations, err := GetExpectations(result.Day)
+	if err != nil {
+		verification.Err = err
+		return verification
+	}
+
+	if result.Err != nil {
+		verification.Err = result.Err
+		return verification
+	}
+
+	for _, partResult := range result.Parts {
+		expectation := expectations[partResult.Part]
+		verification.Parts = append(verification.Parts, PartVerification{
+			PartResult: partResult,
+			Expected:   expectation.Answer,
+			Verdict:    expectation.Check(partResult),
+		})
+	}
+
+	return verification
+}
+
+// Read the expectations of a registered day from the file 'answers.txt' in its package.
+// A day without that file has no expectations
+func GetExpectations(nrDay string) (Expectations, error) {
+	day, exists := registeredDays[nrDay]
+	if !exists {
+		return nil, fmt.Errorf("day '%s' is not registered", nrDay)
+	}
+	if day.files == nil {
+		return Expectations{}, nil
+	}
+
+	raw, err := fs.ReadFile(day.files, "answers.txt")
+	if errors.Is(err, fs.ErrNotExist) {
+		return Expectations{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseExpectations(string(raw))
+}
+
+// Parse the content of an answers-file. Every line holds a part, an optional kind and a value:
+//
+//	# Comments and empty lines are ignored
+//	a: 4479
+//	b too low: 1027
+//	b too high: 120339115
+//	b wrong: 1042
+func ParseExpectations(content string) (Expectations, error) {
+	var expectations Expectations = make(Expectations, 2)
+
+	for idx, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		if !found || value == "" {
+			return nil, fmt.Errorf("answers line %d: expected '<part> [kind]: <answer>', got '%s'", idx+1, line)
+		}
+
+		fields := strings.Fields(key)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("answers line %d: missing part in '%s'", idx+1, line)
+		}
+
+		parts, err := ParseParts(fields[0])
+		if err != nil || len(parts) != 1 {
+			return nil, fmt.Errorf("answers line %d: invalid part '%s'", idx+1, fields[0])
+		}
+
+		expectation := expectations[parts[0]]
+		switch kind := strings.Join(fields[1:], " "); kind {
+		case "":
+			if expectation.Answer != "" {
+				return nil, fmt.Errorf("answers line %d: part %s has multiple confirmed answers", idx+1, fields[0])
+			}
+			expectation.Answer = value
+		case "too low":
+			expectation.TooLow = append(expectation.TooLow, value)
+		case "too high":
+			expectation.TooHigh = append(expectation.TooHigh, value)
+		case "wrong":
+			expectation.Wrong = append(expectation.Wrong, value)
+		default:
+			return nil, fmt.Errorf("answers line %d: unknown kind '%s'", idx+1, kind)
+		}
+		expectations[parts[0]] = expectation
+	}
+
+	return expectations, nil
+}
+
+// Judge the result of a part against the expectation
+func (e Expectation) Check(result PartResult) Verdict {
+	if result.Err != nil {
+		return VerdictFailed
+	}
+
+	answer := result.Answer.String()
+	if e.Answer != "" {
+		if answer == e.Answer {
+			return VerdictCorrect
+		}
+		return VerdictRegression
+	}
+
+	for _, wrong := range e.Wrong {
+		if answer == wrong {
+			return VerdictWrong
+		}
+	}
+
+	// Hints about too low or too high only apply to numerical answers
+	number, err := strconv.Atoi(answer)
+	if err != nil {
+		return VerdictUnverified
+	}
+
+	for _, tooLow := range e.TooLow {
+		if limit, err := strconv.Atoi(tooLow); err == nil && number <= limit {
+			return VerdictTooLow
+		}
+	}
+	for _, tooHigh := range e.TooHigh {
+		if limit, err := strconv.Atoi(tooHigh); err == nil && number >= limit {
+			return VerdictTooHigh
+		}
+	}
+
+	return VerdictUnverified
+}
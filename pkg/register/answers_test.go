@@ -0,0 +1,90 @@
+package register_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExpectations(t *testing.T) {
+	assert := assert.New(t)
+
+	expectations, err := register.ParseExpectations(testAnswers())
+
+	assert.NoError(err)
+	assert.Len(expectations, 2)
+	assert.Equal("4479", expectations[register.Part1].Answer)
+	assert.Equal("", expectations[register.Part2].Answer)
+	assert.Equal([]string{"1027", "1030"}, expectations[register.Part2].TooLow)
+	assert.Equal([]string{"1100"}, expectations[register.Part2].TooHigh)
+	assert.Equal([]string{"1042"}, expectations[register.Part2].Wrong)
+}
+
+func TestParseExpectationsInvalid(t *testing.T) {
+	testCases := map[string]string{
+		"a 4479":          "line 1: expected",
+		"\nc: 1":          "line 2: invalid part 'c'",
+		"b too wide: 1":   "line 1: unknown kind 'too wide'",
+		"a: 1\na: 2":      "line 2: part a has multiple confirmed answers",
+		"b too low:":      "line 1: expected",
+		": 12":            "line 1: missing part",
+		"both: 12":        "line 1: invalid part 'both'",
+		"a: 1\n# c\nx: 1": "line 3",
+	}
+
+	for content, expectedError := range testCases {
+		_, err := register.ParseExpectations(content)
+		assert.ErrorContains(t, err, expectedError, content)
+	}
+}
+
+func TestExpectationCheck(t *testing.T) {
+	expectations, _ := register.ParseExpectations(testAnswers())
+
+	testCases := map[register.Part]map[register.Answer]register.Verdict{
+		register.Part1: {
+			register.IntAnswer(4479): register.VerdictCorrect,
+			register.IntAnswer(4480): register.VerdictRegression,
+		},
+		register.Part2: {
+			register.IntAnswer(1027):        register.VerdictTooLow,
+			register.IntAnswer(1029):        register.VerdictTooLow,
+			register.IntAnswer(1042):        register.VerdictWrong,
+			register.IntAnswer(1040):        register.VerdictUnverified,
+			register.IntAnswer(1100):        register.VerdictTooHigh,
+			register.IntAnswer(9999):        register.VerdictTooHigh,
+			register.StringAnswer("ABC"):    register.VerdictUnverified,
+			register.CoordinateAnswer{1, 2}: register.VerdictUnverified,
+		},
+	}
+
+	for part, answers := range testCases {
+		for answer, expectedVerdict := range answers {
+			result := register.PartResult{Part: part, Answer: answer}
+			assert.Equal(t, expectedVerdict, expectations[part].Check(result), answer.String())
+		}
+	}
+
+	failed := register.PartResult{Part: register.Part1, Err: errors.New("no solution")}
+	assert.Equal(t, register.VerdictFailed, expectations[register.Part1].Check(failed))
+}
+
+func TestVerdictIsFailure(t *testing.T) {
+	assert.False(t, register.VerdictCorrect.IsFailure())
+	assert.False(t, register.VerdictUnverified.IsFailure())
+	assert.True(t, register.VerdictRegression.IsFailure())
+	assert.True(t, register.VerdictTooLow.IsFailure())
+}
+
+func testAnswers() string {
+	return `# Answers of the example
+a: 4479
+
+b too low: 1027
+b too low: 1030
+b too high: 1100
+b wrong: 1042
+`
+}
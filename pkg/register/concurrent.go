@@ -0,0 +1,34 @@
+package register
+
+import "sync"
+
+// Execute the days concurrently, with at most nrWorkers days at a time.
+// The results are in the same order as the days, regardless of which day finishes first
+func ExecDays(nrDays []string, nrWorkers int, options ...Option) []Result {
+	var results []Result = make([]Result, len(nrDays))
+	var wg sync.WaitGroup
+
+	if nrWorkers < 1 {
+		nrWorkers = 1
+	}
+
+	indexes := make(chan int)
+	for worker := 0; worker < nrWorkers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for idx := range indexes {
+				results[idx] = ExecDay(nrDays[idx], options...)
+			}
+		}()
+	}
+
+	for idx := range nrDays {
+		indexes <- idx
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
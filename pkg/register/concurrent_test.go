@@ -0,0 +1,57 @@
+package register_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+// Solves slower for lower days, so the days finish in reverse order
+type slowSolver struct {
+	delay time.Duration
+	input string
+}
+
+func (s *slowSolver) Parse(input string) error {
+	s.input = input
+	return nil
+}
+
+func (s *slowSolver) SolvePart1() (register.Answer, error) {
+	time.Sleep(s.delay)
+	return register.StringAnswer(s.input), nil
+}
+
+func (s *slowSolver) SolvePart2() (register.Answer, error) {
+	return register.IntAnswer(len(s.input)), nil
+}
+
+var slowDays []string
+
+func init() {
+	for idx := 0; idx < 5; idx++ {
+		nrDay := fmt.Sprintf("3%d", idx)
+		delay := time.Duration(5-idx) * time.Millisecond
+		register.Day(nrDay, fakeFiles(nrDay), func() register.Solver { return &slowSolver{delay: delay} })
+		slowDays = append(slowDays, nrDay)
+	}
+}
+
+func TestExecDays(t *testing.T) {
+	t.Setenv(register.InputDirEnv, "")
+	nrDays := slowDays
+
+	for _, nrWorkers := range []int{0, 1, 3, 10} {
+		results := register.ExecDays(nrDays, nrWorkers, register.Part1)
+
+		assert.Len(t, results, len(nrDays))
+		for idx, result := range results {
+			assert.NoError(t, result.Err)
+			assert.Equal(t, nrDays[idx], result.Day, nrWorkers)
+			assert.Equal(t, register.StringAnswer("input of "+nrDays[idx]), result.Parts[0].Answer, nrWorkers)
+		}
+	}
+}
@@ -1,31 +1,138 @@
 package register
 
 import (
-	"path/filepath"
-	"runtime"
+	"fmt"
+	"io"
+	"io/fs"
 	"sort"
+	"strings"
 )
 
+type Part uint
+
+// An Option changes how a day is executed. A Part is an option too: it selects that part to be solved
+type Option interface {
+	apply(config *execConfig)
+}
+
+// The options of an execution
+type execConfig struct {
+	parts []Part
+	trace io.Writer
+}
+
+func (p Part) apply(config *execConfig) {
+	config.parts = append(config.parts, p)
+}
+
+func newExecConfig(options []Option) execConfig {
+	var config execConfig
+	for _, option := range options {
+		option.apply(&config)
+	}
+
+	if len(config.parts) == 0 {
+		config.parts = []Part{Part1, Part2}
+	}
+
+	return config
+}
+
+const (
+	Part1 Part = iota + 1
+	Part2
+)
+
+// A Solver parses the input of a puzzle once, and solves both its parts from the parsed input
+type Solver interface {
+	Parse(input string) error
+	SolvePart1() (Answer, error)
+	SolvePart2() (Answer, error)
+}
+
+// The outcome of executing a single part of a day
+type PartResult struct {
+	Part   Part
+	Answer Answer
+	Err    error
+	Stats  Stats
+}
+
+// The outcome of executing a day. Err is set when the input could not be parsed; then Parts is empty
+type Result struct {
+	Day        string
+	Parts      []PartResult
+	Err        error
+	ParseStats Stats
+}
+
 type registeredDay struct {
-	callback  func(string) // Callback function, as defined by the registering function
-	inputFile string       // location of the input-file, should be named 'input.txt' and live in the package-directory
+	newSolver func() Solver // Constructor of the solver, as defined by the registering function
+	files     fs.FS         // Files of the package, with the optional 'input.txt' and 'answers.txt'
 }
 
 // All days, registered by the packages
 var registeredDays = make(map[string]registeredDay)
 
-func Day(nrDay string, exec func(string)) {
-	_, b, _, _ := runtime.Caller(1)
-	packageDir := filepath.Dir(b)
+// Register a day. The files are normally embedded in the day-package, and hold its 'input.txt' and 'answers.txt';
+// they may be nil. Every execution gets its own solver from newSolver, so no state is shared between runs
+func Day(nrDay string, files fs.FS, newSolver func() Solver) {
+	registeredDays[nrDay] = registeredDay{newSolver, files}
+}
+
+// Execute the selected puzzle, by parsing its input and solving the requested parts.
+// When no parts are given, both parts are solved
+func ExecDay(nrDay string, options ...Option) Result {
+	day, exists := registeredDays[nrDay]
+	if !exists {
+		return Result{Day: nrDay, Err: fmt.Errorf("day '%s' is not registered", nrDay)}
+	}
+
+	input, err := loadInput(nrDay, day)
+	if err != nil {
+		return Result{Day: nrDay, Err: err}
+	}
+
+	return execSolver(nrDay, day.newSolver(), input, newExecConfig(options))
+}
+
+// Execute the selected puzzle like ExecDay, but on the given input instead of its inputfile
+func ExecDayWithInput(nrDay string, input string, options ...Option) Result {
+	day, exists := registeredDays[nrDay]
+	if !exists {
+		return Result{Day: nrDay, Err: fmt.Errorf("day '%s' is not registered", nrDay)}
+	}
 
-	registeredDays[nrDay] = registeredDay{exec, packageDir + "/input.txt"}
+	return execSolver(nrDay, day.newSolver(), strings.TrimSuffix(input, "\n"), newExecConfig(options))
 }
 
-// Execute the selected puzzle,
-// by executing the callback-function with the location of the inputfile as parameter
-func ExecDay(nrDay string) {
-	var day registeredDay = registeredDays[nrDay]
-	day.callback(day.inputFile)
+func execSolver(nrDay string, solver Solver, input string, config execConfig) Result {
+	var result Result = Result{Day: nrDay}
+	setTrace(solver, config.trace)
+
+	var err error
+	result.ParseStats = measure(func() { err = solver.Parse(input) })
+	if err != nil {
+		result.Err = fmt.Errorf("parse input: %w", err)
+		return result
+	}
+
+	for _, part := range config.parts {
+		var partResult PartResult = PartResult{Part: part}
+
+		switch part {
+		case Part1:
+			partResult.Stats = measure(func() { partResult.Answer, partResult.Err = solver.SolvePart1() })
+		case Part2:
+			partResult.Stats = measure(func() { partResult.Answer, partResult.Err = solver.SolvePart2() })
+		default:
+			partResult.Err = fmt.Errorf("unknown part: %d", part)
+		}
+
+		result.Parts = append(result.Parts, partResult)
+	}
+
+	return result
 }
 
 func GetAllDays() (nrDays []string) {
@@ -0,0 +1,60 @@
+package register
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The environment variable with the directory to load inputs from, when not set by SetInputDir
+const InputDirEnv string = "AOC_INPUT_DIR"
+
+var ErrMissingInput = errors.New("missing input")
+
+// Directory to load inputs from, with a file named 'day-XX.txt' per day; overrides the environment variable
+var inputDir string
+
+func SetInputDir(dir string) {
+	inputDir = dir
+}
+
+// Load the input of a registered day. The first available source wins:
+//
+//  1. The file 'day-XX.txt' in the directory set by SetInputDir, or else by the environment variable AOC_INPUT_DIR
+//  2. The file 'input.txt', embedded in the day-package
+//
+// When no source provides an input, the returned error wraps ErrMissingInput
+func loadInput(nrDay string, day registeredDay) (string, error) {
+	if dir := getInputDir(); dir != "" {
+		raw, err := os.ReadFile(filepath.Join(dir, fmt.Sprintf("day-%s.txt", nrDay)))
+		if err == nil {
+			return strings.TrimSuffix(string(raw), "\n"), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	if day.files != nil {
+		raw, err := fs.ReadFile(day.files, "input.txt")
+		if err == nil {
+			return strings.TrimSuffix(string(raw), "\n"), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("%w: add 'day-%s.txt' to the inputs directory, or pass it with -input", ErrMissingInput, nrDay)
+}
+
+func getInputDir() string {
+	if inputDir != "" {
+		return inputDir
+	}
+
+	return os.Getenv(InputDirEnv)
+}
@@ -0,0 +1,82 @@
+package register_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	files := fstest.MapFS{
+		"input.txt":   {Data: []byte("embedded\n")},
+		"answers.txt": {Data: []byte("a: 8\nb too low: 7\n")},
+	}
+	register.Day("20", files, func() register.Solver { return &fakeSolver{} })
+	register.Day("21", nil, func() register.Solver { return &fakeSolver{} })
+}
+
+func fakeFiles(nrDay string) fstest.MapFS {
+	return fstest.MapFS{
+		"input.txt": {Data: []byte("input of " + nrDay + "\n")},
+	}
+}
+
+func TestExecDayWithEmbeddedInput(t *testing.T) {
+	t.Setenv(register.InputDirEnv, "")
+
+	result := register.ExecDay("20", register.Part2)
+
+	assert.NoError(t, result.Err)
+	assert.Equal(t, register.StringAnswer("embedded"), result.Parts[0].Answer)
+}
+
+func TestExecDayWithInputDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "day-20.txt"), []byte("from dir\n"), 0o644))
+
+	// The environment variable overrules the embedded input
+	t.Setenv(register.InputDirEnv, dir)
+	result := register.ExecDay("20", register.Part2)
+	assert.Equal(t, register.StringAnswer("from dir"), result.Parts[0].Answer)
+
+	// The configured directory overrules the environment variable
+	register.SetInputDir(t.TempDir())
+	defer register.SetInputDir("")
+	result = register.ExecDay("20", register.Part2)
+	assert.Equal(t, register.StringAnswer("embedded"), result.Parts[0].Answer)
+}
+
+func TestExecDayWithMissingInput(t *testing.T) {
+	t.Setenv(register.InputDirEnv, t.TempDir())
+
+	result := register.ExecDay("21")
+
+	assert.ErrorIs(t, result.Err, register.ErrMissingInput)
+	assert.ErrorContains(t, result.Err, "day-21")
+	assert.Empty(t, result.Parts)
+}
+
+func TestGetExpectations(t *testing.T) {
+	expectations, err := register.GetExpectations("20")
+	assert.NoError(t, err)
+	assert.Equal(t, "8", expectations[register.Part1].Answer)
+
+	expectations, err = register.GetExpectations("21")
+	assert.NoError(t, err)
+	assert.Empty(t, expectations)
+}
+
+func TestVerifyDay(t *testing.T) {
+	t.Setenv(register.InputDirEnv, "")
+
+	verification := register.VerifyDay("20")
+
+	assert.NoError(t, verification.Err)
+	assert.Len(t, verification.Parts, 2)
+	assert.Equal(t, register.VerdictCorrect, verification.Parts[0].Verdict)
+	assert.Equal(t, register.VerdictUnverified, verification.Parts[1].Verdict)
+}
@@ -0,0 +1,93 @@
+package register
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Select registered days by a specification, like "all", "05", "05,07" or "05-13".
+// An empty specification selects the latest registered day
+func SelectDays(spec string) ([]string, error) {
+	var allDays []string = GetAllDays()
+
+	switch spec = strings.TrimSpace(spec); spec {
+	case "":
+		if len(allDays) == 0 {
+			return nil, fmt.Errorf("no days registered")
+		}
+		return allDays[len(allDays)-1:], nil
+	case "all":
+		return allDays, nil
+	}
+
+	var selected []string = make([]string, 0, len(allDays))
+	var seen map[string]bool = make(map[string]bool, len(allDays))
+
+	for _, item := range strings.Split(spec, ",") {
+		days, err := selectItem(strings.TrimSpace(item), allDays)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, day := range days {
+			if !seen[day] {
+				seen[day] = true
+				selected = append(selected, day)
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+func selectItem(item string, allDays []string) ([]string, error) {
+	if from, to, isRange := strings.Cut(item, "-"); isRange {
+		from, to = normalizeDay(from), normalizeDay(to)
+		if from > to {
+			return nil, fmt.Errorf("invalid range of days: '%s'", item)
+		}
+
+		var days []string = make([]string, 0, len(allDays))
+		for _, day := range allDays {
+			if day >= from && day <= to {
+				days = append(days, day)
+			}
+		}
+		if len(days) == 0 {
+			return nil, fmt.Errorf("no registered days in range '%s'", item)
+		}
+
+		return days, nil
+	}
+
+	day := normalizeDay(item)
+	if _, exists := registeredDays[day]; !exists {
+		return nil, fmt.Errorf("day '%s' is not registered", item)
+	}
+
+	return []string{day}, nil
+}
+
+// Pad single digit days with a zero, so "5" selects day "05"
+func normalizeDay(day string) string {
+	day = strings.TrimSpace(day)
+	if len(day) == 1 {
+		return "0" + day
+	}
+
+	return day
+}
+
+// Parse the parts to solve: "a", "b" or "both" (also accepted: "1", "2" and "")
+func ParseParts(spec string) ([]Part, error) {
+	switch strings.ToLower(strings.TrimSpace(spec)) {
+	case "a", "1":
+		return []Part{Part1}, nil
+	case "b", "2":
+		return []Part{Part2}, nil
+	case "both", "":
+		return []Part{Part1, Part2}, nil
+	default:
+		return nil, fmt.Errorf("invalid part '%s', expected a, b or both", spec)
+	}
+}
@@ -0,0 +1,102 @@
+package register_test
+
+import (
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSolver struct {
+	input string
+}
+
+func (s *fakeSolver) Parse(input string) error {
+	s.input = input
+	return nil
+}
+
+func (s *fakeSolver) SolvePart1() (register.Answer, error) {
+	return register.IntAnswer(len(s.input)), nil
+}
+
+func (s *fakeSolver) SolvePart2() (register.Answer, error) {
+	return register.StringAnswer(s.input), nil
+}
+
+func init() {
+	for _, nrDay := range []string{"01", "02", "03", "10"} {
+		register.Day(nrDay, nil, func() register.Solver { return &fakeSolver{} })
+	}
+}
+
+func TestSelectDays(t *testing.T) {
+	testCases := map[string][]string{
+		"1-10":     {"01", "02", "03", "10"},
+		"02":       {"02"},
+		"2":        {"02"},
+		"03,01":    {"03", "01"},
+		"02-10":    {"02", "03", "10"},
+		"1-2,2-3":  {"01", "02", "03"},
+		" 01 , 10": {"01", "10"},
+	}
+
+	for spec, expectedDays := range testCases {
+		days, err := register.SelectDays(spec)
+		assert.NoError(t, err, spec)
+		assert.Equal(t, expectedDays, days, spec)
+	}
+}
+
+// The other test-files register days of their own, so compare with whatever is registered
+func TestSelectAllDays(t *testing.T) {
+	allDays := register.GetAllDays()
+
+	days, err := register.SelectDays("all")
+	assert.NoError(t, err)
+	assert.Equal(t, allDays, days)
+
+	days, err = register.SelectDays("")
+	assert.NoError(t, err)
+	assert.Equal(t, allDays[len(allDays)-1:], days)
+}
+
+func TestSelectDaysInvalid(t *testing.T) {
+	for _, spec := range []string{"04", "10-02", "04-09", "x"} {
+		_, err := register.SelectDays(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestParseParts(t *testing.T) {
+	testCases := map[string][]register.Part{
+		"a":    {register.Part1},
+		"B":    {register.Part2},
+		"2":    {register.Part2},
+		"both": {register.Part1, register.Part2},
+		"":     {register.Part1, register.Part2},
+	}
+
+	for spec, expectedParts := range testCases {
+		parts, err := register.ParseParts(spec)
+		assert.NoError(t, err, spec)
+		assert.Equal(t, expectedParts, parts, spec)
+	}
+
+	_, err := register.ParseParts("c")
+	assert.ErrorContains(t, err, "invalid part 'c'")
+}
+
+func TestExecDayWithInput(t *testing.T) {
+	result := register.ExecDayWithInput("01", "abc\n")
+
+	assert.NoError(t, result.Err)
+	assert.Len(t, result.Parts, 2)
+	assert.Equal(t, register.IntAnswer(3), result.Parts[0].Answer)
+	assert.Equal(t, register.StringAnswer("abc"), result.Parts[1].Answer)
+	assert.Equal(t, result.ParseStats.Add(result.Parts[0].Stats).Add(result.Parts[1].Stats), result.TotalStats())
+
+	result = register.ExecDayWithInput("01", "abc", register.Part2)
+	assert.Len(t, result.Parts, 1)
+	assert.Equal(t, register.Part2, result.Parts[0].Part)
+}
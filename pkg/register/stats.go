@@ -0,0 +1,51 @@
+package register
+
+import (
+	"runtime"
+	"time"
+)
+
+// Resources used by parsing the input, or by solving a part
+type Stats struct {
+	Duration time.Duration
+	Allocs   uint64 // Number of heap allocations
+	Bytes    uint64 // Number of bytes allocated on the heap
+}
+
+func (s Stats) Add(other Stats) Stats {
+	return Stats{
+		Duration: s.Duration + other.Duration,
+		Allocs:   s.Allocs + other.Allocs,
+		Bytes:    s.Bytes + other.Bytes,
+	}
+}
+
+// Total resources used by the day: parsing plus all solved parts
+func (r Result) TotalStats() Stats {
+	var total Stats = r.ParseStats
+	for _, part := range r.Parts {
+		total = total.Add(part.Stats)
+	}
+
+	return total
+}
+
+// Run fn, and measure its wall time and heap allocations.
+// The allocations are counted for the whole process, so they include those of other goroutines
+func measure(fn func()) Stats {
+	var before, after runtime.MemStats
+
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	fn()
+
+	duration := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	return Stats{
+		Duration: duration,
+		Allocs:   after.Mallocs - before.Mallocs,
+		Bytes:    after.TotalAlloc - before.TotalAlloc,
+	}
+}
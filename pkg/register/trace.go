@@ -0,0 +1,26 @@
+package register
+
+import "io"
+
+// A Solver that can explain how it reaches its answers, like the program it runs, implements Tracer
+type Tracer interface {
+	SetTrace(w io.Writer)
+}
+
+type traceOption struct{ w io.Writer }
+
+// Send the trace of the executed days to w; nil disables tracing.
+// The writer is shared by days executed concurrently
+func WithTrace(w io.Writer) Option {
+	return traceOption{w}
+}
+
+func (o traceOption) apply(config *execConfig) {
+	config.trace = o.w
+}
+
+func setTrace(solver Solver, w io.Writer) {
+	if tracer, ok := solver.(Tracer); ok && w != nil {
+		tracer.SetTrace(w)
+	}
+}
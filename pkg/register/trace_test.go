@@ -0,0 +1,54 @@
+package register_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/register"
+	"github.com/stretchr/testify/assert"
+)
+
+// Writes its input to the trace, when solving part 1
+type tracingSolver struct {
+	fakeSolver
+	trace io.Writer
+}
+
+func (s *tracingSolver) SetTrace(w io.Writer) {
+	s.trace = w
+}
+
+func (s *tracingSolver) SolvePart1() (register.Answer, error) {
+	if s.trace != nil {
+		fmt.Fprintf(s.trace, "solving '%s'\n", s.input)
+	}
+	return s.fakeSolver.SolvePart1()
+}
+
+func init() {
+	register.Day("22", fakeFiles("22"), func() register.Solver { return &tracingSolver{} })
+}
+
+func TestExecDayWithTrace(t *testing.T) {
+	t.Setenv(register.InputDirEnv, "")
+	var trace strings.Builder
+
+	result := register.ExecDay("22", register.Part1, register.WithTrace(&trace))
+	register.ExecDay("20", register.Part1, register.WithTrace(&trace)) // Solvers without a trace are run as usual
+
+	assert.NoError(t, result.Parts[0].Err)
+	assert.Equal(t, "solving 'input of 22'\n", trace.String())
+
+	register.ExecDay("22", register.Part1)
+	assert.Equal(t, "solving 'input of 22'\n", trace.String())
+
+	results := register.ExecDays([]string{"22", "22"}, 2, register.WithTrace(&trace), register.Part2)
+	assert.Len(t, results[1].Parts, 1)
+	assert.Equal(t, "solving 'input of 22'\n", trace.String(), "only part 1 writes a trace")
+
+	results = register.ExecDays([]string{"22"}, 1, register.WithTrace(&trace))
+	assert.Len(t, results[0].Parts, 2)
+	assert.Equal(t, "solving 'input of 22'\nsolving 'input of 22'\n", trace.String())
+}
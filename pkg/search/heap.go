@@ -0,0 +1,58 @@
+// Package search holds a generic priority queue, and the shortest path searches built on it
+package search
+
+import "container/heap"
+
+// A priority queue: Pop returns the item for which less holds against all other items
+type Heap[T any] struct {
+	items *heapItems[T]
+}
+
+func NewHeap[T any](less func(a, b T) bool, items ...T) *Heap[T] {
+	var h *Heap[T] = &Heap[T]{
+		items: &heapItems[T]{
+			items: append(make([]T, 0, len(items)), items...),
+			less:  less,
+		},
+	}
+	heap.Init(h.items)
+
+	return h
+}
+
+func (h *Heap[T]) Len() int {
+	return h.items.Len()
+}
+
+func (h *Heap[T]) Push(item T) {
+	heap.Push(h.items, item)
+}
+
+// Remove and return the first item. Panics when the heap is empty
+func (h *Heap[T]) Pop() T {
+	return heap.Pop(h.items).(T)
+}
+
+// Return the first item, without removing it. Panics when the heap is empty
+func (h *Heap[T]) Peek() T {
+	return h.items.items[0]
+}
+
+// The adapter for container/heap
+type heapItems[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h heapItems[T]) Len() int           { return len(h.items) }
+func (h heapItems[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h heapItems[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *heapItems[T]) Push(x any)        { h.items = append(h.items, x.(T)) }
+
+func (h *heapItems[T]) Pop() any {
+	old := h.items
+	n := len(old)
+	x := old[n-1]
+	h.items = old[0 : n-1]
+	return x
+}
@@ -0,0 +1,39 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeap(t *testing.T) {
+	h := search.NewHeap(func(a, b int) bool { return a < b }, 5, 3, 8)
+	h.Push(1)
+	h.Push(6)
+
+	assert.Equal(t, 5, h.Len())
+	assert.Equal(t, 1, h.Peek())
+
+	var popped []int
+	for h.Len() > 0 {
+		popped = append(popped, h.Pop())
+	}
+	assert.Equal(t, []int{1, 3, 5, 6, 8}, popped)
+}
+
+func TestHeapWithStructs(t *testing.T) {
+	type job struct {
+		name     string
+		priority int
+	}
+
+	h := search.NewHeap(func(a, b job) bool { return a.priority > b.priority })
+	h.Push(job{"low", 1})
+	h.Push(job{"high", 9})
+	h.Push(job{"mid", 5})
+
+	assert.Equal(t, "high", h.Pop().name)
+	assert.Equal(t, "mid", h.Pop().name)
+	assert.Equal(t, "low", h.Pop().name)
+}
@@ -0,0 +1,86 @@
+package search
+
+import "errors"
+
+var ErrNoPath = errors.New("no path found")
+
+// A transition to a neighbouring state, and what it costs
+type Edge[S comparable] struct {
+	To   S
+	Cost int
+}
+
+// The cheapest path found, from the start up to and including the goal
+type Solution[S comparable] struct {
+	Cost int
+	Path []S
+}
+
+// Find the cheapest path from start to a state that satisfies isGoal.
+// Costs may not be negative
+func Dijkstra[S comparable](start S, isGoal func(S) bool, neighbours func(S) []Edge[S]) (Solution[S], error) {
+	return AStar(start, isGoal, neighbours, func(S) int { return 0 })
+}
+
+// Find the cheapest path from start to a state that satisfies isGoal, exploring the states with the lowest
+// cost plus heuristic first. The heuristic estimates the cost to the goal; to find the cheapest path, it may
+// never estimate higher than the actual cost. Costs may not be negative
+func AStar[S comparable](start S, isGoal func(S) bool, neighbours func(S) []Edge[S], heuristic func(S) int) (Solution[S], error) {
+	type node struct {
+		state    S
+		cost     int
+		priority int
+	}
+
+	var bestCosts map[S]int = map[S]int{start: 0}
+	var previous map[S]S = make(map[S]S)
+	var queue *Heap[node] = NewHeap(
+		func(a, b node) bool { return a.priority < b.priority },
+		node{state: start, cost: 0, priority: heuristic(start)},
+	)
+
+	for queue.Len() > 0 {
+		current := queue.Pop()
+
+		// A cheaper path to this state was found after it was queued
+		if current.cost > bestCosts[current.state] {
+			continue
+		}
+
+		if isGoal(current.state) {
+			return Solution[S]{
+				Cost: current.cost,
+				Path: buildPath(start, current.state, previous),
+			}, nil
+		}
+
+		for _, edge := range neighbours(current.state) {
+			newCost := current.cost + edge.Cost
+			if knownCost, exists := bestCosts[edge.To]; exists && knownCost <= newCost {
+				continue
+			}
+
+			bestCosts[edge.To] = newCost
+			previous[edge.To] = current.state
+			queue.Push(node{state: edge.To, cost: newCost, priority: newCost + heuristic(edge.To)})
+		}
+	}
+
+	return Solution[S]{}, ErrNoPath
+}
+
+func buildPath[S comparable](start, goal S, previous map[S]S) []S {
+	var path []S = []S{goal}
+
+	for current := goal; current != start; {
+		current = previous[current]
+		path = append(path, current)
+	}
+
+	// Reverse, to start at the start
+	for left, right := 0, len(path)-1; left < right; left, right = left+1, right-1 {
+		path[left], path[right] = path[right], path[left]
+	}
+
+	return path
+}
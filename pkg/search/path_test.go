@@ -0,0 +1,96 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/grid"
+	"github.com/ewoutquax/advent-of-code-2018/pkg/search"
+	"github.com/stretchr/testify/assert"
+)
+
+// A small weighted graph, where the direct route is not the cheapest
+func testGraph() map[string][]search.Edge[string] {
+	return map[string][]search.Edge[string]{
+		"A": {{To: "B", Cost: 7}, {To: "C", Cost: 9}, {To: "F", Cost: 14}},
+		"B": {{To: "A", Cost: 7}, {To: "C", Cost: 10}, {To: "D", Cost: 15}},
+		"C": {{To: "A", Cost: 9}, {To: "B", Cost: 10}, {To: "D", Cost: 11}, {To: "F", Cost: 2}},
+		"D": {{To: "B", Cost: 15}, {To: "C", Cost: 11}, {To: "E", Cost: 6}},
+		"E": {{To: "D", Cost: 6}, {To: "F", Cost: 9}},
+		"F": {{To: "A", Cost: 14}, {To: "C", Cost: 2}, {To: "E", Cost: 9}},
+	}
+}
+
+func TestDijkstra(t *testing.T) {
+	graph := testGraph()
+
+	solution, err := search.Dijkstra(
+		"A",
+		func(s string) bool { return s == "E" },
+		func(s string) []search.Edge[string] { return graph[s] },
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 20, solution.Cost)
+	assert.Equal(t, []string{"A", "C", "F", "E"}, solution.Path)
+}
+
+func TestDijkstraStartIsGoal(t *testing.T) {
+	graph := testGraph()
+
+	solution, err := search.Dijkstra(
+		"A",
+		func(s string) bool { return s == "A" },
+		func(s string) []search.Edge[string] { return graph[s] },
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, solution.Cost)
+	assert.Equal(t, []string{"A"}, solution.Path)
+}
+
+func TestDijkstraNoPath(t *testing.T) {
+	graph := testGraph()
+
+	_, err := search.Dijkstra(
+		"A",
+		func(s string) bool { return s == "Z" },
+		func(s string) []search.Edge[string] { return graph[s] },
+	)
+
+	assert.ErrorIs(t, err, search.ErrNoPath)
+}
+
+func TestAStarOnGrid(t *testing.T) {
+	// Find the way around the wall
+	lines := []string{
+		"S...",
+		"###.",
+		"G...",
+	}
+	maze := grid.ParseDense(lines)
+	goal := grid.Pt(0, 2)
+
+	var nrExplored int = 0
+	solution, err := search.AStar(
+		grid.Pt(0, 0),
+		func(p grid.Point) bool { return p == goal },
+		func(p grid.Point) []search.Edge[grid.Point] {
+			nrExplored++
+
+			var edges []search.Edge[grid.Point]
+			for _, neighbour := range p.Neighbours4() {
+				if char := maze.Get(neighbour); char == '.' || char == 'G' {
+					edges = append(edges, search.Edge[grid.Point]{To: neighbour, Cost: 1})
+				}
+			}
+			return edges
+		},
+		func(p grid.Point) int { return p.ManhattanDistance(goal) },
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8, solution.Cost)
+	assert.Len(t, solution.Path, 9)
+	assert.Equal(t, grid.Pt(3, 1), solution.Path[4])
+	assert.LessOrEqual(t, nrExplored, 9)
+}
@@ -1,7 +1,5 @@
 package utils
 
-import "strconv"
-
 type Filterable interface {
 	int | string
 }
@@ -21,7 +19,7 @@ func Unique[T Filterable](items []T) (list []T) {
 
 // Convert a string to an int, without the nasty error-check
 func ConvStrToI(s string) (i int) {
-	i, err := strconv.Atoi(s)
+	i, err := ParseInt(s)
 	check(err)
 	return
 }
@@ -6,35 +6,95 @@ import (
 )
 
 func ReadFileAsNumbers(baseDir string) (numbers []int) {
-	var lines []string = ReadFileAsLines(baseDir)
-
-	for _, string := range lines {
-		numbers = append(numbers, ConvStrToI(string))
-	}
+	numbers, err := ReadNumbers(baseDir)
+	check(err)
 
 	return
 }
 
 func ReadFileAsBlocks(baseDir string) (blocks [][]string) {
-	var block_inputs []string = strings.Split(readFile(baseDir), "\n\n")
+	blocks, err := ReadBlocks(baseDir)
+	check(err)
 
-	for _, block_input := range block_inputs {
-		blocks = append(blocks, strings.Split(block_input, "\n"))
-	}
 	return
 }
 
 func ReadFileAsLines(inputFile string) []string {
-	return strings.Split(readFile(inputFile), "\n")
+	lines, err := ReadLines(inputFile)
+	check(err)
+
+	return lines
 }
 
 func ReadFileAsLine(inputFile string) string {
+	line, err := ReadLine(inputFile)
+	check(err)
+
+	return line
+}
+
+// Read a file with a number per line. A line that is not a number gives a *ParseError with its line number
+func ReadNumbers(inputFile string) ([]int, error) {
+	lines, err := ReadLines(inputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var numbers []int = make([]int, 0, len(lines))
+	for idx, line := range lines {
+		number, err := ParseIntAt(line, idx+1, 1)
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+	}
+
+	return numbers, nil
+}
+
+func ReadBlocks(inputFile string) ([][]string, error) {
+	content, err := readFile(inputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return SplitBlocks(content), nil
+}
+
+func ReadLines(inputFile string) ([]string, error) {
+	content, err := readFile(inputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return SplitLines(content), nil
+}
+
+// Read the complete file as a single string, without the trailing newline
+func ReadLine(inputFile string) (string, error) {
 	return readFile(inputFile)
 }
 
-func readFile(inputFile string) string {
+func readFile(inputFile string) (string, error) {
 	raw, err := os.ReadFile(inputFile)
-	check(err)
+	if err != nil {
+		return "", err
+	}
 
-	return strings.TrimSuffix(string(raw), "\n")
+	return strings.TrimSuffix(string(raw), "\n"), nil
+}
+
+// Split the content of an inputfile into lines
+func SplitLines(input string) []string {
+	return strings.Split(input, "\n")
+}
+
+// Split the content of an inputfile into blocks of lines, separated by empty lines
+func SplitBlocks(input string) (blocks [][]string) {
+	var block_inputs []string = strings.Split(input, "\n\n")
+
+	for _, block_input := range block_inputs {
+		blocks = append(blocks, strings.Split(block_input, "\n"))
+	}
+	return
 }
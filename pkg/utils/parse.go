@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A failure to parse a part of an input, pointing to where it occurred
+type ParseError struct {
+	Line   int    // Line number, starting at 1; 0 when unknown
+	Column int    // Column, starting at 1; 0 when unknown
+	Input  string // The text that could not be parsed
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	var location string
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		location = fmt.Sprintf("line %d, column %d: ", e.Line, e.Column)
+	case e.Line > 0:
+		location = fmt.Sprintf("line %d: ", e.Line)
+	}
+
+	return fmt.Sprintf("%sparse '%s': %v", location, e.Input, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Convert a string to an int. Surrounding whitespace is ignored
+func ParseInt(s string) (int, error) {
+	return ParseIntAt(s, 0, 0)
+}
+
+// Convert a string to an int, like ParseInt. Upon failure, the error holds the given line and column
+func ParseIntAt(s string, line, column int) (int, error) {
+	i, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		var cause error = err
+		if numError, ok := err.(*strconv.NumError); ok {
+			cause = numError.Err
+		}
+
+		return 0, &ParseError{Line: line, Column: column, Input: s, Err: cause}
+	}
+
+	return i, nil
+}
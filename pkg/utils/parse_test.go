@@ -0,0 +1,51 @@
+package utils_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/ewoutquax/advent-of-code-2018/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInt(t *testing.T) {
+	nr, err := utils.ParseInt(" -42")
+
+	assert.NoError(t, err)
+	assert.Equal(t, -42, nr)
+}
+
+func TestParseIntAtInvalid(t *testing.T) {
+	_, err := utils.ParseIntAt("4x2", 3, 7)
+
+	var parseError *utils.ParseError
+	assert.ErrorAs(t, err, &parseError)
+	assert.Equal(t, 3, parseError.Line)
+	assert.Equal(t, 7, parseError.Column)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.EqualError(t, err, "line 3, column 7: parse '4x2': invalid syntax")
+
+	_, err = utils.ParseInt("")
+	assert.EqualError(t, err, "parse '': invalid syntax")
+}
+
+func TestReadNumbers(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "input.txt")
+	assert.NoError(t, os.WriteFile(inputFile, []byte("1\n2\n3\n"), 0o644))
+
+	numbers, err := utils.ReadNumbers(inputFile)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, numbers)
+
+	assert.NoError(t, os.WriteFile(inputFile, []byte("1\ntwo\n3\n"), 0o644))
+	_, err = utils.ReadNumbers(inputFile)
+	assert.EqualError(t, err, "line 2, column 1: parse 'two': invalid syntax")
+}
+
+func TestReadLinesMissingFile(t *testing.T) {
+	_, err := utils.ReadLines(filepath.Join(t.TempDir(), "missing.txt"))
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}